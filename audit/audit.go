@@ -0,0 +1,305 @@
+// Package audit keeps a tamper-evident, append-only log of launch decisions.
+//
+// The log is a file of JSON lines. Each entry holds the hash of the
+// previous entry, so that modifying or removing an entry breaks the chain
+// of every following one. A head file next to the log records the last
+// entry, so that removing entries at the end is detected too. Entries and
+// head are optionally signed with an Ed25519 key.
+package audit
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/tdurieux/go-decide/decide"
+)
+
+// Entry is a line of the log.
+type Entry struct {
+	Seq           int           `json:"SEQ"`
+	Time          time.Time     `json:"TIME"`
+	InputSHA256   string        `json:"INPUT_SHA256"`
+	Input         decide.INPUT  `json:"INPUT"`
+	Result        decide.Decide `json:"RESULT"`
+	EngineVersion string        `json:"ENGINE_VERSION"`
+	PrevHash      string        `json:"PREV_HASH"`
+	// Hash is the SHA-256 of the entry without Hash and Signature.
+	Hash string `json:"HASH"`
+	// Signature is the Ed25519 signature of Hash, if the log is signed.
+	Signature string `json:"SIGNATURE,omitempty"`
+}
+
+// head is the content of the head file: the last entry of the log.
+type head struct {
+	Seq       int    `json:"SEQ"`
+	Hash      string `json:"HASH"`
+	Signature string `json:"SIGNATURE,omitempty"`
+}
+
+// HeadPath returns the path of the head file of the log at path.
+func HeadPath(path string) string {
+	return path + ".head"
+}
+
+// hash computes the hash of e, ignoring its Hash and Signature.
+func (e Entry) hash() (string, error) {
+	e.Hash = ""
+	e.Signature = ""
+	content, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Log appends entries to an audit log. It is safe for concurrent use,
+// also by several processes appending to the same log.
+type Log struct {
+	mu   sync.Mutex
+	path string
+	key  ed25519.PrivateKey
+	seq  int
+	last string
+}
+
+// Open opens the log at path, creating it if needed. Entries are signed
+// with key when it is not nil. The log is verified first, with the public
+// key of key, and is not opened when it was modified or truncated, so that
+// appending to it cannot make it valid again.
+func Open(path string, key ed25519.PrivateKey) (*Log, error) {
+	l := &Log{path: path, key: key}
+	err := l.locked(func(*os.File) error {
+		return l.load()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// locked runs fn with the log open for appending and locked against the
+// other processes.
+func (l *Log) locked(fn func(f *os.File) error) error {
+	f, err := os.OpenFile(l.path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := lock(f); err != nil {
+		return fmt.Errorf("unable to lock %s: %s", l.path, err)
+	}
+	defer unlock(f)
+	return fn(f)
+}
+
+// load verifies the log and continues its chain from its head.
+func (l *Log) load() error {
+	h, err := readHead(l.path)
+	if os.IsNotExist(err) {
+		entries, err := Read(l.path)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			// A new log.
+			l.seq, l.last = 0, ""
+			return nil
+		}
+	}
+	var public ed25519.PublicKey
+	if l.key != nil {
+		public = l.key.Public().(ed25519.PublicKey)
+	}
+	if _, err := Verify(l.path, public); err != nil {
+		return fmt.Errorf("%s: %w", l.path, err)
+	}
+	l.seq, l.last = h.Seq, h.Hash
+	return nil
+}
+
+// Append records the decision of input and returns the new entry.
+func (l *Log) Append(input decide.INPUT, result decide.Decide, at time.Time) (Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	inputHash, err := decide.Hash(input)
+	if err != nil {
+		return Entry{}, err
+	}
+	var entry Entry
+	err = l.locked(func(f *os.File) error {
+		// Another process may have appended to the log since it was
+		// loaded.
+		h, err := readHead(l.path)
+		if err != nil && !os.IsNotExist(err) || err == nil && (h.Seq != l.seq || h.Hash != l.last) {
+			if err := l.load(); err != nil {
+				return err
+			}
+		}
+		entry = Entry{
+			Seq:           l.seq + 1,
+			Time:          at.UTC(),
+			InputSHA256:   inputHash,
+			Input:         input,
+			Result:        result,
+			EngineVersion: decide.Version,
+			PrevHash:      l.last,
+		}
+		if entry.Hash, err = entry.hash(); err != nil {
+			return err
+		}
+		entry.Signature = l.sign(entry.Hash)
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(append(line, '\n')); err != nil {
+			return err
+		}
+		if err := f.Sync(); err != nil {
+			return err
+		}
+		return l.writeHead(head{Seq: entry.Seq, Hash: entry.Hash})
+	})
+	if err != nil {
+		return Entry{}, err
+	}
+	l.seq = entry.Seq
+	l.last = entry.Hash
+	return entry, nil
+}
+
+func (l *Log) sign(hash string) string {
+	if l.key == nil {
+		return ""
+	}
+	return hex.EncodeToString(ed25519.Sign(l.key, []byte(hash)))
+}
+
+// writeHead replaces the head file atomically.
+func (l *Log) writeHead(h head) error {
+	h.Signature = l.sign(fmt.Sprintf("%d:%s", h.Seq, h.Hash))
+	content, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	tmp := HeadPath(l.path) + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, HeadPath(l.path))
+}
+
+// Read returns the entries of the log at path without verifying them.
+func Read(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64 * 1024), 64 * 1024 * 1024)
+	for line := 1; scanner.Scan(); line++ {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return entries, &VerifyError{Line: line, Msg: fmt.Sprintf("invalid entry: %s", err)}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// VerifyError reports the first entry of the log that fails verification.
+type VerifyError struct {
+	// Line is the line of the entry in the log, 0 for the head file.
+	Line int
+	Msg  string
+}
+
+func (e *VerifyError) Error() string {
+	if e.Line == 0 {
+		return "head: " + e.Msg
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// Verify checks the hash chain of the log at path against its head file
+// and, when key is not nil, the signatures of entries and head. It
+// returns the number of verified entries.
+func Verify(path string, key ed25519.PublicKey) (int, error) {
+	entries, err := Read(path)
+	if err != nil {
+		return len(entries), err
+	}
+	prev := ""
+	for i, entry := range entries {
+		line := i + 1
+		if entry.Seq != line {
+			return i, &VerifyError{line, fmt.Sprintf("sequence %d, expected %d", entry.Seq, line)}
+		}
+		if entry.PrevHash != prev {
+			return i, &VerifyError{line, "previous hash does not match, an entry was modified or removed"}
+		}
+		hash, err := entry.hash()
+		if err != nil {
+			return i, err
+		}
+		if hash != entry.Hash {
+			return i, &VerifyError{line, "hash does not match, the entry was modified"}
+		}
+		inputHash, err := decide.Hash(entry.Input)
+		if err != nil {
+			return i, err
+		}
+		if inputHash != entry.InputSHA256 {
+			return i, &VerifyError{line, "input hash does not match the input"}
+		}
+		if key != nil && !verifySignature(key, entry.Hash, entry.Signature) {
+			return i, &VerifyError{line, "invalid signature"}
+		}
+		prev = entry.Hash
+	}
+
+	h, err := readHead(path)
+	if err != nil {
+		return len(entries), &VerifyError{0, fmt.Sprintf("unable to read the head file: %s", err)}
+	}
+	if h.Seq != len(entries) || h.Hash != prev {
+		return len(entries), &VerifyError{0, fmt.Sprintf("the head is entry %d but the log ends at entry %d, the log was truncated or extended", h.Seq, len(entries))}
+	}
+	if key != nil && !verifySignature(key, fmt.Sprintf("%d:%s", h.Seq, h.Hash), h.Signature) {
+		return len(entries), &VerifyError{0, "invalid signature"}
+	}
+	return len(entries), nil
+}
+
+// readHead reads the head file of the log at path.
+func readHead(path string) (head, error) {
+	var h head
+	content, err := ioutil.ReadFile(HeadPath(path))
+	if err != nil {
+		return h, err
+	}
+	if err := json.Unmarshal(content, &h); err != nil {
+		return h, fmt.Errorf("invalid head file: %s", err)
+	}
+	return h, nil
+}
+
+func verifySignature(key ed25519.PublicKey, message string, signature string) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(key, []byte(message), sig)
+}
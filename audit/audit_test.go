@@ -0,0 +1,173 @@
+package audit
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tdurieux/go-decide/decide"
+)
+
+// writeLog appends n decisions to a new log and returns its path.
+func writeLog(t *testing.T, key ed25519.PrivateKey, n int) string {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l, err := Open(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		input := decide.Generate(int64(i))
+		d := decide.Decide{}
+		if err := d.Decide(input); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := l.Append(input, d, time.Unix(int64(i), 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func TestVerify(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	path := writeLog(t, private, 3)
+	n, err := Verify(path, public)
+	if err != nil || n != 3 {
+		t.Error("Expected 3 valid entries, got", n, err)
+		return
+	}
+
+	// reopening the log continues the chain
+	l, err := Open(path, private)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	input := decide.Generate(3)
+	d := decide.Decide{}
+	d.Decide(input)
+	entry, err := l.Append(input, d, time.Unix(3, 0))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if entry.Seq != 4 {
+		t.Error("Expected entry 4, got", entry.Seq)
+		return
+	}
+	if n, err := Verify(path, public); err != nil || n != 4 {
+		t.Error("Expected 4 valid entries, got", n, err)
+		return
+	}
+
+	other, _, _ := ed25519.GenerateKey(rand.Reader)
+	if _, err := Verify(path, other); err == nil {
+		t.Error("Expected the signature check to fail with another key")
+		return
+	}
+}
+
+func TestVerifyModification(t *testing.T) {
+	path := writeLog(t, nil, 3)
+	content, _ := ioutil.ReadFile(path)
+	modified := strings.Replace(string(content), `"LAUNCH":"NO"`, `"LAUNCH":"YES"`, 1)
+	if modified == string(content) {
+		modified = strings.Replace(string(content), `"LAUNCH":"YES"`, `"LAUNCH":"NO"`, 1)
+	}
+	ioutil.WriteFile(path, []byte(modified), 0600)
+	_, err := Verify(path, nil)
+	if verr, ok := err.(*VerifyError); !ok || verr.Line == 0 {
+		t.Error("Expected a modified entry, got", err)
+		return
+	}
+}
+
+func TestVerifyRemoval(t *testing.T) {
+	path := writeLog(t, nil, 3)
+	content, _ := ioutil.ReadFile(path)
+	lines := strings.SplitAfter(string(content), "\n")
+
+	// removing an entry in the middle breaks the chain
+	ioutil.WriteFile(path, []byte(lines[0] + lines[2]), 0600)
+	if _, err := Verify(path, nil); err == nil {
+		t.Error("Expected a removed entry to be detected")
+		return
+	}
+
+	// removing the last entry is detected by the head
+	ioutil.WriteFile(path, []byte(lines[0] + lines[1]), 0600)
+	_, err := Verify(path, nil)
+	if verr, ok := err.(*VerifyError); !ok || verr.Line != 0 {
+		t.Error("Expected a truncation, got", err)
+		return
+	}
+
+	os.Remove(HeadPath(path))
+	if _, err := Verify(path, nil); err == nil {
+		t.Error("Expected a missing head to be detected")
+		return
+	}
+}
+
+func TestOpenTampered(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	path := writeLog(t, private, 3)
+	content, _ := ioutil.ReadFile(path)
+	lines := strings.SplitAfter(string(content), "\n")
+	ioutil.WriteFile(path, []byte(lines[0]), 0600)
+
+	// appending to a truncated log must not make it valid again
+	if _, err := Open(path, private); err == nil {
+		t.Error("Expected a truncated log to be refused")
+		return
+	}
+	if _, err := Verify(path, public); err == nil {
+		t.Error("Expected the truncation to still be detected")
+		return
+	}
+
+	// the entries must be signed by the key of the log
+	path = writeLog(t, private, 2)
+	_, other, _ := ed25519.GenerateKey(rand.Reader)
+	if _, err := Open(path, other); err == nil {
+		t.Error("Expected a log signed with another key to be refused")
+		return
+	}
+}
+
+func TestAppendShared(t *testing.T) {
+	path := writeLog(t, nil, 1)
+	a, err := Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// two logs on the same file, as serve and run in two processes,
+	// continue the chain of each other
+	for i, l := range []*Log{a, b, a, b} {
+		input := decide.Generate(int64(i))
+		d := decide.Decide{}
+		d.Decide(input)
+		entry, err := l.Append(input, d, time.Unix(int64(i), 0))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if entry.Seq != i + 2 {
+			t.Error("Expected entry", i + 2, "got", entry.Seq)
+			return
+		}
+	}
+	if n, err := Verify(path, nil); err != nil || n != 5 {
+		t.Error("Expected 5 valid entries, got", n, err)
+		return
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package audit
+
+import "os"
+
+// lock does not lock f on the platforms without flock: only the appends
+// of a single process are serialized, by the mutex of the Log.
+func lock(f *os.File) error {
+	return nil
+}
+
+func unlock(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"github.com/tdurieux/go-decide/audit"
+	"github.com/tdurieux/go-decide/sign"
+	"fmt"
+	"os"
+)
+
+// openAuditLog opens the audit log at path, signing its entries with the
+// private key at keyPath when set. It returns nil when path is empty.
+func openAuditLog(path string, keyPath string) (*audit.Log, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if keyPath == "" {
+		return audit.Open(path, nil)
+	}
+	key, err := sign.LoadPrivateKey(keyPath)
+	if err != nil {
+		return nil, inputError{err}
+	}
+	return audit.Open(path, key)
+}
+
+func keygenCmd(args []string) int {
+	flags := newFlagSet("keygen", "",
+		"Creates an Ed25519 key pair to sign audit logs and results.")
+	privatePath := flags.String("key", "decide.key", "the path of the private key to create")
+	publicPath := flags.String("pub", "decide.pub", "the path of the public key to create")
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+	if err := sign.GenerateKey(*privatePath, *publicPath); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInternal
+	}
+	return exitOK
+}
+
+func verifyLogCmd(args []string) int {
+	flags := newFlagSet("verify-log", "log",
+		"Verifies the hash chain of an audit log, detecting modified, removed\n" +
+		"or truncated entries, and the signatures when a public key is given.")
+	publicPath := flags.String("pub", "", "the path of the public key that signed the log")
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitInput
+	}
+	var err error
+	var n int
+	if *publicPath == "" {
+		n, err = audit.Verify(flags.Arg(0), nil)
+	} else {
+		key, kerr := sign.LoadPublicKey(*publicPath)
+		if kerr != nil {
+			fmt.Fprintln(os.Stderr, kerr)
+			return exitInput
+		}
+		n, err = audit.Verify(flags.Arg(0), key)
+	}
+	if err != nil {
+		fmt.Printf("%s: %s (%d valid entries before)\n", flags.Arg(0), err, n)
+		return exitNo
+	}
+	fmt.Printf("%s: OK, %d entries\n", flags.Arg(0), n)
+	return exitOK
+}
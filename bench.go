@@ -0,0 +1,102 @@
+package main
+
+import (
+	"github.com/tdurieux/go-decide/bench"
+	"github.com/tdurieux/go-decide/decide"
+	"fmt"
+	"os"
+)
+
+func benchCmd(args []string) int {
+	flags := newFlagSet("bench", "",
+		"Benchmarks each rule and the whole decision on a corpus of inputs and\n" +
+		"compares them with a baseline, failing when one is slower than the\n" +
+		"baseline by more than the threshold.")
+	inputPath := flags.String("input", "input", "the input file or directory of the corpus")
+	generated := flags.Int("generate", 0, "benchmark this number of generated inputs instead of the corpus")
+	numPoints := flags.Int("numpoints", 100, "the NUMPOINTS of the generated inputs")
+	baselinePath := flags.String("baseline", "", "the path of the baseline to compare with")
+	savePath := flags.String("save", "", "the path where the results are saved as a new baseline")
+	threshold := flags.Float64("threshold", 0.2, "the slowdown over the baseline considered a regression, 0.2 for 20%")
+	benchtime := flags.String("benchtime", "1s", "the run time of each benchmark, or Nx for N iterations")
+	allocsOnly := flags.Bool("allocs-only", false, "compare only the allocations with the baseline, not the times, which depend on the machine")
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+	duration, err := bench.ParseDuration(*benchtime)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid -benchtime:", err)
+		return exitInput
+	}
+
+	engine := &decide.Engine{MaxPoints: max(*numPoints, decide.DefaultMaxPoints)}
+	var inputs []decide.INPUT
+	if *generated > 0 {
+		config := decide.DefaultGeneratorConfig()
+		config.NumPoints = decide.Range{Min: float64(*numPoints), Max: float64(*numPoints)}
+		config.MaxPoints = engine.MaxPoints
+		for seed := 0; seed < *generated; seed++ {
+			input, err := decide.GenerateWith(int64(seed), config)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return exitInput
+			}
+			inputs = append(inputs, input)
+		}
+	} else {
+		files, err := inputFiles(*inputPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitInput
+		}
+		for _, file := range files {
+			// Invalid inputs are skipped by the benchmarks.
+			if input, err := getInput(file); err == nil {
+				inputs = append(inputs, input)
+			}
+		}
+	}
+	var baseline []bench.Result
+	if *baselinePath != "" {
+		var err error
+		if baseline, err = bench.Load(*baselinePath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitInput
+		}
+	}
+
+	benchmarks, err := bench.Benchmarks(engine, inputs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInput
+	}
+	results, err := bench.Run(benchmarks, duration)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInternal
+	}
+	for _, r := range results {
+		fmt.Println(r)
+	}
+	if *savePath != "" {
+		if err := bench.Save(*savePath, results); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitInternal
+		}
+	}
+	if baseline == nil {
+		return exitOK
+	}
+	failed := false
+	for _, r := range bench.Compare(baseline, results, *threshold) {
+		if *allocsOnly && !r.Allocs {
+			continue
+		}
+		fmt.Println("REGRESSION", r)
+		failed = true
+	}
+	if failed {
+		return exitNo
+	}
+	return exitOK
+}
@@ -0,0 +1,230 @@
+// Package bench measures the performance of the rules and of the whole
+// decision, and compares the measures with a stored baseline.
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tdurieux/go-decide/decide"
+)
+
+// Benchmark is a named operation to measure.
+type Benchmark struct {
+	Name string
+	// Op runs the operation n times.
+	Op func(n int) error
+}
+
+// Benchmarks returns a benchmark per rule, Rule0 to Rule14, and a Decide
+// benchmark of the whole decision. An operation evaluates every input
+// that engine accepts.
+func Benchmarks(engine *decide.Engine, inputs []decide.INPUT) ([]Benchmark, error) {
+	var decisions []decide.Decide
+	var valid []decide.INPUT
+	for _, input := range inputs {
+		d, err := engine.Decide(context.Background(), input)
+		if err != nil {
+			continue
+		}
+		decisions = append(decisions, d)
+		valid = append(valid, input)
+	}
+	if len(valid) == 0 {
+		return nil, fmt.Errorf("no valid input to benchmark")
+	}
+
+	var benchmarks []Benchmark
+	for lic := 0; lic < decide.NB_LIC; lic++ {
+		lic := lic
+		benchmarks = append(benchmarks, Benchmark{fmt.Sprintf("Rule%d", lic), func(n int) error {
+			for i := 0; i < n; i++ {
+				for _, d := range decisions {
+					if _, err := d.Rule(lic); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}})
+	}
+	benchmarks = append(benchmarks, Benchmark{"Decide", func(n int) error {
+		for i := 0; i < n; i++ {
+			for _, input := range valid {
+				if _, err := engine.Decide(context.Background(), input); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}})
+	return benchmarks, nil
+}
+
+// Result is the measure of a benchmark.
+type Result struct {
+	Name        string  `json:"NAME"`
+	NsPerOp     float64 `json:"NS_PER_OP"`
+	AllocsPerOp int64   `json:"ALLOCS_PER_OP"`
+	BytesPerOp  int64   `json:"BYTES_PER_OP"`
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("%-8s %14.0f ns/op %10d B/op %8d allocs/op", r.Name, r.NsPerOp, r.BytesPerOp, r.AllocsPerOp)
+}
+
+// Duration is how long a benchmark runs: for at least Time, or for
+// exactly Iterations operations when it is not 0.
+type Duration struct {
+	Time       time.Duration
+	Iterations int
+}
+
+// ParseDuration parses a duration such as 1s, or Nx for N iterations, as
+// the -benchtime flag of go test.
+func ParseDuration(s string) (Duration, error) {
+	if strings.HasSuffix(s, "x") {
+		n, err := strconv.Atoi(strings.TrimSuffix(s, "x"))
+		if err != nil || n <= 0 {
+			return Duration{}, fmt.Errorf("invalid number of iterations %q", s)
+		}
+		return Duration{Iterations: n}, nil
+	}
+	t, err := time.ParseDuration(s)
+	if err != nil || t <= 0 {
+		return Duration{}, fmt.Errorf("invalid duration %q", s)
+	}
+	return Duration{Time: t}, nil
+}
+
+// Run runs each benchmark for d, growing the number of operations until
+// they last d.Time like go test does, and measures the time and the
+// allocations of an operation.
+func Run(benchmarks []Benchmark, d Duration) ([]Result, error) {
+	results := make([]Result, len(benchmarks))
+	for i, benchmark := range benchmarks {
+		n := d.Iterations
+		if n == 0 {
+			n = 1
+		}
+		for {
+			r, elapsed, err := measure(benchmark, n)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", benchmark.Name, err)
+			}
+			if d.Iterations != 0 || elapsed >= d.Time || n >= 1e9 {
+				results[i] = r
+				break
+			}
+			// Aim 20% past the time from the last run, growing at most
+			// a hundred times.
+			next := int(float64(n) * 1.2 * float64(d.Time) / float64(max(elapsed, 1)))
+			n = min(max(next, n + 1), n * 100)
+		}
+	}
+	return results, nil
+}
+
+// measure runs the operation of benchmark n times.
+func measure(benchmark Benchmark, n int) (Result, time.Duration, error) {
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	err := benchmark.Op(n)
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	r := Result{
+		Name:        benchmark.Name,
+		NsPerOp:     float64(elapsed.Nanoseconds()) / float64(n),
+		AllocsPerOp: int64(after.Mallocs - before.Mallocs) / int64(n),
+		BytesPerOp:  int64(after.TotalAlloc - before.TotalAlloc) / int64(n),
+	}
+	return r, elapsed, err
+}
+
+// Save writes results to path as a baseline.
+func Save(path string, results []Result) error {
+	content, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// Load reads a baseline written by Save.
+func Load(path string) ([]Result, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var results []Result
+	if err := json.Unmarshal(content, &results); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return results, nil
+}
+
+// Regression is a benchmark slower than its baseline, or that allocates
+// more.
+type Regression struct {
+	Name     string
+	Baseline float64
+	Current  float64
+	// Allocs is set when the regression is in the allocations per
+	// operation rather than in the time.
+	Allocs bool
+}
+
+// Ratio returns the relative slowdown, e.g. 0.25 for 25% slower.
+func (r Regression) Ratio() float64 {
+	return r.Current / r.Baseline - 1
+}
+
+func (r Regression) String() string {
+	if r.Allocs {
+		return fmt.Sprintf("%s: %.0f allocs/op, baseline %.0f allocs/op", r.Name, r.Current, r.Baseline)
+	}
+	return fmt.Sprintf("%s: %.0f ns/op, baseline %.0f ns/op (+%.1f%%)", r.Name, r.Current, r.Baseline, r.Ratio() * 100)
+}
+
+// AllocThreshold is the increase of the allocations per operation over
+// the baseline considered a regression. Unlike the time, the number of
+// allocations does not depend on the machine, but the runtime allocates
+// a little during a measure.
+const AllocThreshold = 0.1
+
+// Compare returns the benchmarks of current that are slower than in
+// baseline by more than threshold, e.g. 0.2 for 20%, or that allocate
+// more than AllocThreshold more often, sorted by name. Benchmarks missing
+// from baseline are ignored.
+func Compare(baseline []Result, current []Result, threshold float64) []Regression {
+	base := make(map[string]Result, len(baseline))
+	for _, r := range baseline {
+		base[r.Name] = r
+	}
+	var regressions []Regression
+	for _, r := range current {
+		b, ok := base[r.Name]
+		if !ok || b.NsPerOp <= 0 {
+			continue
+		}
+		if r.NsPerOp > b.NsPerOp * (1 + threshold) {
+			regressions = append(regressions, Regression{Name: r.Name, Baseline: b.NsPerOp, Current: r.NsPerOp})
+		}
+		if float64(r.AllocsPerOp) > float64(b.AllocsPerOp) * (1 + AllocThreshold) {
+			regressions = append(regressions, Regression{Name: r.Name, Baseline: float64(b.AllocsPerOp), Current: float64(r.AllocsPerOp), Allocs: true})
+		}
+	}
+	sort.SliceStable(regressions, func(i, j int) bool {
+		return regressions[i].Name < regressions[j].Name
+	})
+	return regressions
+}
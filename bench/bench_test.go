@@ -0,0 +1,121 @@
+package bench
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tdurieux/go-decide/decide"
+)
+
+func TestCompare(t *testing.T) {
+	baseline := []Result{{Name: "Rule0", NsPerOp: 100}, {Name: "Rule1", NsPerOp: 100}, {Name: "Decide", NsPerOp: 1000}}
+	current := []Result{{Name: "Rule0", NsPerOp: 119}, {Name: "Rule1", NsPerOp: 130}, {Name: "Decide", NsPerOp: 1500}, {Name: "Rule2", NsPerOp: 1e9}}
+	regressions := Compare(baseline, current, 0.2)
+	if len(regressions) != 2 || regressions[0].Name != "Decide" || regressions[1].Name != "Rule1" {
+		t.Error("Expected Decide and Rule1 to regress, got", regressions)
+		return
+	}
+	if ratio := regressions[1].Ratio(); ratio < 0.29 || ratio > 0.31 {
+		t.Error("Expected a 30% regression, got", ratio)
+		return
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	results := []Result{{Name: "Rule0", NsPerOp: 12.5, AllocsPerOp: 1, BytesPerOp: 16}}
+	if err := Save(path, results); err != nil {
+		t.Error(err)
+		return
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(loaded) != 1 || loaded[0] != results[0] {
+		t.Error("Expected", results, "got", loaded)
+		return
+	}
+}
+
+func TestBenchmarks(t *testing.T) {
+	benchmarks, err := Benchmarks(&decide.Engine{}, []decide.INPUT{decide.Generate(1), {}})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(benchmarks) != decide.NB_LIC + 1 || benchmarks[decide.NB_LIC].Name != "Decide" {
+		t.Error("Expected a benchmark per rule and Decide, got", len(benchmarks))
+		return
+	}
+	if _, err := Benchmarks(&decide.Engine{}, []decide.INPUT{{}}); err == nil {
+		t.Error("Expected an error without valid input")
+		return
+	}
+}
+
+func TestRun(t *testing.T) {
+	benchmarks, err := Benchmarks(&decide.Engine{}, []decide.INPUT{decide.Generate(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := Run(benchmarks, Duration{Iterations: 3})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(results) != len(benchmarks) || results[decide.NB_LIC].Name != "Decide" || results[decide.NB_LIC].NsPerOp <= 0 || results[decide.NB_LIC].AllocsPerOp == 0 {
+		t.Error("Expected the time and the allocations of each benchmark, got", results)
+		return
+	}
+
+	results, err = Run(benchmarks[:1], Duration{Time: 20 * time.Millisecond})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if results[0].NsPerOp <= 0 {
+		t.Error("Expected a measure of Rule0, got", results)
+		return
+	}
+
+	// A rule that fails is not timed as a fast success.
+	failing := Benchmark{"Rule0", func(n int) error { return errors.New("Invalid length1") }}
+	if _, err := Run([]Benchmark{failing}, Duration{Iterations: 3}); err == nil {
+		t.Error("Expected the error of the operation")
+		return
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	for s, expected := range map[string]Duration{"1s": {Time: time.Second}, "100x": {Iterations: 100}, "250ms": {Time: 250 * time.Millisecond}} {
+		d, err := ParseDuration(s)
+		if err != nil || d != expected {
+			t.Error("Expected", expected, "for", s, "got", d, err)
+			return
+		}
+	}
+	for _, s := range []string{"", "0x", "-1s", "fast"} {
+		if _, err := ParseDuration(s); err == nil {
+			t.Error("Expected", s, "to be rejected")
+			return
+		}
+	}
+}
+
+func TestCompareAllocs(t *testing.T) {
+	baseline := []Result{{Name: "Decide", NsPerOp: 1000, AllocsPerOp: 10}}
+	current := []Result{{Name: "Decide", NsPerOp: 900, AllocsPerOp: 12}}
+	if regressions := Compare(baseline, []Result{{Name: "Decide", NsPerOp: 900, AllocsPerOp: 11}}, 0.2); regressions != nil {
+		t.Error("Expected a small variation of the allocations to be tolerated, got", regressions)
+		return
+	}
+	regressions := Compare(baseline, current, 0.2)
+	if len(regressions) != 1 || !regressions[0].Allocs {
+		t.Error("Expected an allocation regression, got", regressions)
+		return
+	}
+}
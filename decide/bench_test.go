@@ -0,0 +1,197 @@
+package decide
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// largeInput returns an input of n points on the positive x axis, on
+// which every rule scans every window without meeting its condition:
+// the angles are straight, the triangles flat and the thresholds out of
+// reach. TestLargeInput checks it.
+func largeInput(n int) INPUT {
+	input := Generate(1)
+	input.NumPoints = n
+	input.Points = make([][2]float64, n)
+	for i := range input.Points {
+		input.Points[i] = [2]float64{float64(i), 0}
+	}
+	p := &input.Parameters
+	p.LENGTH1 = 1e12
+	p.RADIUS1 = 1e12
+	p.AREA1 = 1e12
+	p.DIST = 1e12
+	p.EPSILON = 0.1
+	p.QUADS = 1
+	p.Q_PTS = 1000
+	// Below hullMinPoints, Rule6 examines the N_PTS - 2 points of every
+	// window: see BenchmarkRule6Wide for a large N_PTS.
+	p.N_PTS = 10
+	p.K_PTS = 1000
+	return input
+}
+
+// TestLargeInput checks that no rule stops before the last window of the
+// input of the large benchmarks.
+func TestLargeInput(t *testing.T) {
+	input := largeInput(2000)
+	e := Engine{MaxPoints: input.NumPoints}
+	d := Decide{}
+	if err := e.decide(context.Background(), &d, input); err != nil {
+		t.Error(err)
+		return
+	}
+	d.Trace = &Trace{}
+	for lic := 0; lic < NB_LIC; lic++ {
+		r := d.evaluateRule(lic, false)
+		last := input.NumPoints - licSpan(lic, input.Parameters)
+		if r.err != nil || r.value || r.scan.last != last {
+			t.Error("Expected Rule", lic, "to scan up to the window", last, "got", r.scan.last, r.value, r.err)
+			return
+		}
+	}
+}
+
+func BenchmarkDecideLarge(b *testing.B) {
+	input := largeInput(1000000)
+	e := Engine{MaxPoints: input.NumPoints}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := e.Decide(context.Background(), input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkRuleLarge(b *testing.B, rule func(Decide) (bool, error)) {
+	benchmarkRule(b, largeInput(1000000), rule)
+}
+
+func benchmarkRule(b *testing.B, input INPUT, rule func(Decide) (bool, error)) {
+	e := Engine{MaxPoints: input.NumPoints}
+	d := Decide{}
+	if err := e.decide(context.Background(), &d, input); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := rule(d); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// corpus returns the valid inputs of the input directory.
+func corpus(b *testing.B) []INPUT {
+	paths, err := filepath.Glob("../input/input*.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	var inputs []INPUT
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var input INPUT
+		if err := json.Unmarshal(content, &input); err != nil {
+			b.Fatal(path, err)
+		}
+		if Validate(input) == nil {
+			inputs = append(inputs, input)
+		}
+	}
+	if len(inputs) == 0 {
+		b.Skip("no input in ../input")
+	}
+	return inputs
+}
+
+func BenchmarkDecideCorpus(b *testing.B) {
+	inputs := corpus(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, input := range inputs {
+			d := Decide{}
+			if err := d.Decide(input); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkDecideGenerated(b *testing.B) {
+	config := DefaultGeneratorConfig()
+	config.NumPoints = Range{100, 100}
+	input, err := GenerateWith(1, config)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		d := Decide{}
+		if err := d.Decide(input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkRules evaluates each rule on every input of the corpus.
+func BenchmarkRules(b *testing.B) {
+	inputs := corpus(b)
+	decisions := make([]Decide, len(inputs))
+	for i, input := range inputs {
+		if err := decisions[i].Decide(input); err != nil {
+			b.Fatal(err)
+		}
+	}
+	for lic := 0; lic < NB_LIC; lic++ {
+		b.Run(fmt.Sprintf("Rule%d", lic), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, d := range decisions {
+					if _, err := d.Rule(lic); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkRulesLarge evaluates each rule on an input of a million points.
+func BenchmarkRulesLarge(b *testing.B) {
+	for lic := 0; lic < NB_LIC; lic++ {
+		b.Run(fmt.Sprintf("Rule%d", lic), func(b *testing.B) {
+			benchmarkRuleLarge(b, func(d Decide) (bool, error) {
+				return d.Rule(lic)
+			})
+		})
+	}
+}
+
+// BenchmarkRule6Wide evaluates Rule6 with N_PTS = NUMPOINTS / 2 on points
+// of a parabola, every one of which is a vertex of the hulls searched by
+// Rule6: the time grows with NUMPOINTS × log² NUMPOINTS, rather than with
+// its square for a scan of the points of every set.
+func BenchmarkRule6Wide(b *testing.B) {
+	for _, n := range []int{1000, 10000, 100000} {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			input := largeInput(n)
+			for i := range input.Points {
+				x := float64(i) / float64(n)
+				input.Points[i] = [2]float64{x, x * x}
+			}
+			input.Parameters.N_PTS = n / 2
+			input.Parameters.Q_PTS = 2
+			input.Parameters.K_PTS = 1
+			benchmarkRule(b, input, Decide.Rule6)
+		})
+	}
+}
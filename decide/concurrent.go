@@ -0,0 +1,118 @@
+package decide
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RuleError is the error of the evaluation of a LIC.
+type RuleError struct {
+	LIC int
+	Err error
+}
+
+func (e RuleError) Error() string {
+	return e.Err.Error()
+}
+
+func (e RuleError) Unwrap() error {
+	return e.Err
+}
+
+// RuleErrors holds the errors of the LICs evaluated concurrently, by
+// increasing LIC. The first one is the error a sequential evaluation
+// would have returned.
+type RuleErrors []RuleError
+
+func (e RuleErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = fmt.Sprintf("LIC %d: %s", err.LIC, err.Err)
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e RuleErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// PanicError is the error of a LIC whose evaluation panicked.
+type PanicError struct {
+	Value interface{}
+}
+
+func (e PanicError) Error() string {
+	return fmt.Sprintf("internal error: %v", e.Value)
+}
+
+// evaluateRuleSafely is like evaluateRule but turns a panic of the rule
+// into an error, so that a rule cannot kill the process from a worker.
+func (d Decide) evaluateRuleSafely(lic int, skip bool) (r ruleResult) {
+	start := time.Now()
+	defer func() {
+		if v := recover(); v != nil {
+			r = ruleResult{err: PanicError{v}, start: start, end: time.Now(), scan: &scan{exit: -1}}
+		}
+	}()
+	return d.evaluateRule(lic, skip)
+}
+
+// performCMVConcurrently evaluates the LICs with a pool of
+// e.Concurrency goroutines. The results are reported in the order of
+// the LICs once every rule is evaluated, so that they do not depend on
+// the scheduling of the goroutines.
+func (d *Decide) performCMVConcurrently(ctx context.Context, e *Engine) error {
+	var results [NB_LIC]ruleResult
+	skip := d.skipped(e)
+	var evaluated [NB_LIC]bool
+	lics := make(chan int, NB_LIC)
+	for i := 0; i < NB_LIC; i++ {
+		lics <- i
+	}
+	close(lics)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(e.Concurrency, NB_LIC); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range lics {
+				if ctx.Err() != nil {
+					continue
+				}
+				results[i] = d.evaluateRuleSafely(i, skip[i])
+				evaluated[i] = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	var cmv Cmv
+	var errs RuleErrors
+	for i := 0; i < NB_LIC; i++ {
+		if !evaluated[i] {
+			return ctx.Err()
+		}
+		d.observeRule(e, i, results[i])
+		if results[i].err != nil {
+			errs = append(errs, RuleError{i, results[i].err})
+		}
+		cmv[i] = results[i].value
+	}
+	if errs != nil {
+		return errs
+	}
+	d.CMV = cmv
+	d.NotEvaluated = notEvaluated(skip)
+	return nil
+}
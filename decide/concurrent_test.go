@@ -0,0 +1,123 @@
+package decide
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestConcurrent is meant to run with the race detector: go test -race.
+func TestConcurrent(t *testing.T) {
+	sequential := Engine{Trace: true}
+	concurrent := Engine{Trace: true, Concurrency: 4}
+	for seed := int64(0); seed < 100; seed++ {
+		input := Generate(seed)
+		a, errA := sequential.Decide(context.Background(), input)
+		b, errB := concurrent.Decide(context.Background(), input)
+		if errA != nil || errB != nil {
+			t.Error("Unexpected error for seed", seed, errA, errB)
+			return
+		}
+		if a.CMV != b.CMV || a.PUM != b.PUM || a.FUV != b.FUV || a.Launch != b.Launch {
+			t.Error("The concurrent and sequential results differ for seed", seed, Compare(a, b))
+			return
+		}
+		if !reflect.DeepEqual(a.Trace.PUM, b.Trace.PUM) || !reflect.DeepEqual(a.Trace.FUV, b.Trace.FUV) {
+			t.Error("The concurrent and sequential traces differ for seed", seed)
+			return
+		}
+		for i := range a.Trace.Rules {
+			ra, rb := a.Trace.Rules[i], b.Trace.Rules[i]
+			if ra.LIC != rb.LIC || ra.Windows != rb.Windows || ra.ExitIndex != rb.ExitIndex || ra.Value != rb.Value {
+				t.Error("The traces of LIC", i, "differ for seed", seed, ra, rb)
+				return
+			}
+		}
+	}
+}
+
+func TestConcurrentErrors(t *testing.T) {
+	input := Generate(1)
+	input.Parameters.LENGTH1 = -1
+	input.Parameters.QUADS = 4
+	input.Parameters.AREA2 = -1
+
+	sequential := Engine{}
+	_, errA := sequential.Decide(context.Background(), input)
+	concurrent := Engine{Concurrency: 3}
+	_, errB := concurrent.Decide(context.Background(), input)
+
+	var errs RuleErrors
+	if !errors.As(errB, &errs) {
+		t.Error("Expected RuleErrors, got", errB)
+		return
+	}
+	if len(errs) != 3 || errs[0].LIC != 0 || errs[1].LIC != 4 || errs[2].LIC != 14 {
+		t.Error("Expected the errors of LICs 0, 4 and 14, got", errs)
+		return
+	}
+	if errs[0].Err.Error() != errA.Error() {
+		t.Error("Expected the first error to be the sequential one,", errA, "got", errs[0])
+		return
+	}
+	var validation *ValidationError
+	if !errors.As(errB, &validation) || validation.Field != "LENGTH1" {
+		t.Error("Expected the ValidationError of LENGTH1, got", validation)
+		return
+	}
+}
+
+func TestConcurrentContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	e := Engine{Concurrency: 4}
+	if _, err := e.Decide(ctx, Generate(1)); err != context.Canceled {
+		t.Error("Expected context.Canceled, got", err)
+		return
+	}
+}
+
+func TestConcurrentSharedParameters(t *testing.T) {
+	// LIC 12 reads the K_PTS of LIC 7, which is not evaluated first
+	// when the LICs are evaluated concurrently.
+	input := Generate(1)
+	input.Parameters.K_PTS = -5
+	e := Engine{Concurrency: 4}
+	_, err := e.Decide(context.Background(), input)
+	var errs RuleErrors
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].LIC != 7 || errs[1].LIC != 12 {
+		t.Error("Expected the errors of LICs 7 and 12, got", err)
+		return
+	}
+}
+
+func TestConcurrentPanic(t *testing.T) {
+	// An input that skipped the checks of the engine, with fewer points
+	// than NUMPOINTS.
+	input := Generate(1)
+	input.NumPoints = 10
+	input.Points = input.Points[:3]
+	input.Parameters.A_PTS, input.Parameters.B_PTS = 1, 1
+	d := Decide{input: input}
+	r := d.evaluateRuleSafely(8, false)
+	var panicked PanicError
+	if !errors.As(r.err, &panicked) {
+		t.Error("Expected the panic of LIC 8 to be reported as an error, got", r.err)
+		return
+	}
+
+	// The trace of the panic has no set of points.
+	d.Trace = &Trace{}
+	err := d.performCMVConcurrently(context.Background(), &Engine{Concurrency: 4})
+	if !errors.As(err, &panicked) {
+		t.Error("Expected the panic of LIC 8 to be reported with a trace, got", err)
+		return
+	}
+	for _, rule := range d.Trace.Rules {
+		if rule.LIC == 8 && (rule.Windows != 0 || rule.ExitIndex != -1 || rule.Error == "") {
+			t.Error("Expected the trace of LIC 8 to report the panic, got", rule)
+			return
+		}
+	}
+}
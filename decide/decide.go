@@ -1,10 +1,11 @@
 package decide
 
 import (
-	"errors"
+	"context"
 	"math"
-	"reflect"
 	"fmt"
+	"sync"
+	"time"
 )
 
 const NB_LIC = 15
@@ -13,8 +14,8 @@ type Command string
 
 const (
 	ANDD Command = "ANDD"
-	ORR Command = "NOTUSED"
-	NOTUSED Command = "ORR"
+	ORR Command = "ORR"
+	NOTUSED Command = "NOTUSED"
 )
 
 type Parameters struct {
@@ -45,6 +46,10 @@ type INPUT struct {
 	LCM        map[string][NB_LIC]Command `json:"LCM"`
 	PUV        [NB_LIC]bool `json:"PUV"`
 	Parameters Parameters `json:"PARAMETERS"`
+	// RandomSeed is the seed the input was generated from, if any. The seeds
+	// of the input corpus come from another generator, so GenerateWith does
+	// not reproduce those inputs from them.
+	RandomSeed *int64 `json:"random_seed,omitempty"`
 }
 
 type Pum [NB_LIC][NB_LIC]bool
@@ -53,22 +58,83 @@ type Cmv [NB_LIC]bool
 
 type Decide struct {
 	input  INPUT
+	mode   Mode
+	evaluation Evaluation
+	// maxPoints and bounds are the limits of the engine on the points.
+	maxPoints int
+	bounds *Bounds
+	lazy   bool
+	// geometry is the geometry of the evaluation, nil for the float
+	// evaluation of Rule0 to Rule14.
+	geometry geometry
+	scan   *scan
+	// ctx is the context of the decision being evaluated, checked by the
+	// rules between their sets of points.
+	ctx    context.Context
+	// kDistances holds the distances between the points separated by
+	// K_PTS consecutive intervening points, shared by Rule7 and Rule12.
+	kDistances *lazyDistances
+	// hulls holds the convex hulls of the points searched by Rule6 when
+	// N_PTS is large.
+	hulls  *lazyHulls
 	Launch string `json:"LAUNCH"`
 	CMV    Cmv `json:"CMV"`
 	PUM    Pum `json:"PUM"`
 	FUV    Fuv `json:"FUV"`
+	// NotEvaluated lists the LICs skipped by a lazy evaluation, whose
+	// CMV entries are false and PUM entries computed as if they were.
+	NotEvaluated []int `json:"NOT_EVALUATED,omitempty"`
+	Provenance *Provenance `json:"PROVENANCE,omitempty"`
+	Trace  *Trace `json:"TRACE,omitempty"`
 }
 
 func (d *Decide) Decide(input INPUT) error {
-	if input.NumPoints < 2 || input.NumPoints > 100 {
-		return errors.New("Invalid NumPoints value.")
+	return d.DecideContext(context.Background(), input)
+}
+
+// DecideContext is like Decide but gives up between two LICs once
+// ctx is done, returning the error of ctx.
+func (d *Decide) DecideContext(ctx context.Context, input INPUT) error {
+	var e Engine
+	return e.decide(ctx, d, input)
+}
+
+func (d *Decide) evaluate(ctx context.Context, e *Engine, input INPUT) error {
+	if err := e.checkInput(input); err != nil {
+		return err
 	}
-	if (len(input.Points) != input.NumPoints) {
-		return errors.New("Invalid NumPoints value different from the actual number of points.")
+	g, err := e.evaluation().geometry()
+	if err != nil {
+		return err
 	}
 	d.input = input
-
-	err := d.performCMV()
+	d.mode = e.mode()
+	d.evaluation = e.evaluation()
+	d.maxPoints = e.maxPoints()
+	d.bounds = e.Bounds
+	d.lazy = e.Lazy
+	d.geometry = nil
+	if d.evaluation != EvaluationFloat {
+		d.geometry = g
+	}
+	d.NotEvaluated = nil
+	d.kDistances = nil
+	if k := input.Parameters.K_PTS; k >= 1 && k <= input.NumPoints - 2 {
+		d.kDistances = &lazyDistances{points: input.Points, gap: k + 1}
+	}
+	d.hulls = nil
+	if n := input.Parameters.N_PTS; n >= hullMinPoints && n <= input.NumPoints {
+		d.hulls = &lazyHulls{points: input.Points}
+	}
+	d.Provenance = nil
+	d.Trace = nil
+	if e.Trace {
+		d.Trace = &Trace{}
+	}
+
+	d.ctx = ctx
+	err = d.performCMV(ctx, e)
+	d.ctx = nil
 	if err != nil {
 		return err
 	}
@@ -86,20 +152,21 @@ func (d *Decide) performPUM() {
 		cmvi := d.CMV[i]
 		for j := 0; j < NB_LIC; j++ {
 			lcm := d.input.LCM[fmt.Sprintf("%d", i)][j]
+			cmvj := d.CMV[j]
 			if (lcm == NOTUSED) {
 				d.PUM[i][j] = true
-				continue
-			}
-			cmvj := d.CMV[j]
-			if (lcm == ANDD) {
+			} else if (lcm == ANDD) {
 				d.PUM[i][j] = cmvi && cmvj
-				continue
-			}
-			if (lcm == ORR) {
+			} else if (lcm == ORR) {
 				d.PUM[i][j] = cmvi || cmvj
-				continue
+			} else {
+				// A missing or unknown LCM entry is never satisfied,
+				// whatever the previous decision left in the PUM.
+				d.PUM[i][j] = false
+			}
+			if d.Trace != nil {
+				d.Trace.PUM = append(d.Trace.PUM, PUMStep{i, j, lcm, [2]bool{cmvi, cmvj}, d.PUM[i][j]})
 			}
-
 		}
 	}
 }
@@ -108,49 +175,150 @@ func (d *Decide) performFUV() {
 	for i := 0; i < NB_LIC; i++ {
 		if !d.input.PUV[i] {
 			d.FUV[i] = true
+			if d.Trace != nil {
+				d.Trace.FUV = append(d.Trace.FUV, FUVStep{i, false, -1, true})
+			}
 			continue
 		}
 		fuv := true
+		blocking := -1
 		for j := 0; j < NB_LIC && fuv; j++ {
 			if i == j {
 				continue
 			}
 			fuv = d.PUM[i][j]
+			if !fuv {
+				blocking = j
+			}
 		}
 		d.FUV[i] = fuv
+		if d.Trace != nil {
+			d.Trace.FUV = append(d.Trace.FUV, FUVStep{i, true, blocking, fuv})
+		}
 	}
 }
 
-func (d *Decide) performCMV() error {
+func (d *Decide) performCMV(ctx context.Context, e *Engine) error {
+	if e.Concurrency > 1 {
+		return d.performCMVConcurrently(ctx, e)
+	}
+
 	var cmv Cmv
+	skip := d.skipped(e)
 
 	for i := 0; i < NB_LIC; i++ {
-		decideValue := reflect.ValueOf(d)
-		methodName := fmt.Sprintf("Rule%d", i)
-		method := decideValue.MethodByName(methodName)
-		ruleMethod := method.Interface().(func() (bool, error))
-		value, err := ruleMethod()
-		if err != nil {
+		if err := ctx.Err(); err != nil {
 			return err
 		}
-		cmv[i] = value
+		r := d.evaluateRule(i, skip[i])
+		d.observeRule(e, i, r)
+		if r.err != nil {
+			return r.err
+		}
+		cmv[i] = r.value
 	}
 
 	d.CMV = cmv
+	d.NotEvaluated = notEvaluated(skip)
 	return nil
 }
 
+// skipped returns the LICs that a lazy evaluation skips.
+func (d *Decide) skipped(e *Engine) [NB_LIC]bool {
+	var skip [NB_LIC]bool
+	if !e.Lazy {
+		return skip
+	}
+	relevant := Relevant(d.input)
+	for i := range skip {
+		skip[i] = !relevant[i]
+	}
+	return skip
+}
+
+func notEvaluated(skip [NB_LIC]bool) []int {
+	var lics []int
+	for i, s := range skip {
+		if s {
+			lics = append(lics, i)
+		}
+	}
+	return lics
+}
+
+// ruleResult is the evaluation of a rule.
+type ruleResult struct {
+	value bool
+	err   error
+	start time.Time
+	end   time.Time
+	scan  *scan
+	// skipped reports a LIC not evaluated, whose parameters are only
+	// checked.
+	skipped bool
+}
+
+// evaluateRule evaluates the LIC lic, or only checks its parameters when
+// skip is set. It only reads d, so that the rules can be evaluated
+// concurrently.
+func (d Decide) evaluateRule(lic int, skip bool) ruleResult {
+	if skip {
+		return ruleResult{err: ruleConstraints[lic](d.input), skipped: true}
+	}
+	if d.Trace != nil {
+		d.scan = &scan{exit: -1}
+	}
+	r := ruleResult{start: time.Now(), scan: d.scan}
+	r.value, r.err = d.Rule(lic)
+	r.end = time.Now()
+	return r
+}
+
+// observeRule reports the evaluation of the LIC lic to the observer, the
+// logger and the trace.
+func (d *Decide) observeRule(e *Engine, lic int, r ruleResult) {
+	if r.skipped {
+		e.logger().Debug("rule skipped", "lic", lic, "error", r.err)
+		return
+	}
+	if e.Observer != nil {
+		e.Observer.ObserveRule(lic, r.value, r.end.Sub(r.start), r.err)
+	}
+	e.logger().Debug("rule evaluated", "lic", lic, "value", r.value, "elapsed", r.end.Sub(r.start), "error", r.err)
+	if d.Trace != nil {
+		d.Trace.addRule(lic, r.start, r.end, r.scan, r.value, r.err)
+	}
+}
+
+// rules holds Rule0 to Rule14, by LIC.
+var rules = [NB_LIC]func(Decide) (bool, error){
+	Decide.Rule0, Decide.Rule1, Decide.Rule2, Decide.Rule3, Decide.Rule4,
+	Decide.Rule5, Decide.Rule6, Decide.Rule7, Decide.Rule8, Decide.Rule9,
+	Decide.Rule10, Decide.Rule11, Decide.Rule12, Decide.Rule13, Decide.Rule14,
+}
+
+// Rule evaluates the LIC lic on the input of the last decision, with its
+// evaluation.
+func (d Decide) Rule(lic int) (bool, error) {
+	if d.geometry != nil {
+		return d.evaluateSets(lic, d.geometry)
+	}
+	return rules[lic](d)
+}
+
 // There exists at least one set of two consecutive data points
 // that are a distance greater than the length, LENGTH1, apart.
 func (d Decide) Rule0() (bool, error) {
-	// (0 ≤ LENGTH1)
-	if d.input.Parameters.LENGTH1 < 0 {
-		return false, errors.New("Invalid length1")
+	if err := checkRule0(d.input); err != nil {
+		return false, err
 	}
 	for i, c := range d.input.Points {
 		if (i >= d.input.NumPoints - 1) {
 			break;
 		}
+		if d.examine(i) {
+			return false, d.ctx.Err()
+		}
 		next := d.input.Points[i + 1]
 		if computeDistancePointToPoint(c, next) > d.input.Parameters.LENGTH1 {
 			return true, nil
@@ -162,26 +330,20 @@ func (d Decide) Rule0() (bool, error) {
 // There exists at least one set of three consecutive data points
 // that cannot all be contained within or on a circle of radius RADIUS1.
 func (d Decide) Rule1() (bool, error) {
-	// (0 ≤ RADIUS1)
-	if d.input.Parameters.RADIUS1 < 0 {
-		return false, errors.New("Invalid RADIUS1")
+	if err := checkRule1(d.input); err != nil {
+		return false, err
 	}
 	for i, p1 := range d.input.Points {
 		if (i >= d.input.NumPoints - 2) {
 			break;
 		}
+		if d.examine(i) {
+			return false, d.ctx.Err()
+		}
 		p2 := d.input.Points[i + 1]
 		p3 := d.input.Points[i + 2]
 
-		// center of the 3 points
-		var pc [2]float64
-		pc[0] = (p1[0] + p2[0] + p3[0]) / 3;
-		pc[1] = (p1[1] + p2[1] + p3[1]) / 3;
-
-		r1 := computeDistancePointToPoint(p1, pc)
-		r2 := computeDistancePointToPoint(p2, pc)
-		r3 := computeDistancePointToPoint(p3, pc)
-		if (r1 > d.input.Parameters.RADIUS1 || r2 > d.input.Parameters.RADIUS1 || r3 > d.input.Parameters.RADIUS1) {
+		if outsideCircle(p1, p2, p3, d.input.Parameters.RADIUS1) {
 			return true, nil
 		}
 	}
@@ -194,33 +356,27 @@ func (d Decide) Rule1() (bool, error) {
 // If either the first point or the last point (or both) coincides with the vertex,
 // the angle is undefined and the LIC is not satisfied by those three points
 func (d Decide) Rule2() (bool, error) {
-	// (0 ≤ EPSILON < PI)
-	if d.input.Parameters.EPSILON < 0 || d.input.Parameters.EPSILON >= math.Pi {
-		return false, errors.New("Invalid EPSILON")
+	if err := checkRule2(d.input); err != nil {
+		return false, err
 	}
 	for i, a := range d.input.Points {
 		if (i >= d.input.NumPoints - 2) {
 			break;
 		}
+		if d.examine(i) {
+			return false, d.ctx.Err()
+		}
 		b := d.input.Points[i + 1]
 		c := d.input.Points[i + 2]
 
-		// http://stackoverflow.com/questions/3486172/angle-between-3-points
-		ab := [2]float64{b[0] - a[0], b[1] - a[1]}
-		cb := [2]float64{b[0] - c[0], b[1] - c[1]}
-
-		dot := (ab[0] * cb[0] + ab[1] * cb[1])
-		cross := (ab[0] * cb[1] - ab[1] * cb[0])
-
-		angle := math.Atan2(cross, dot)
-
+		satisfied, undefined := angleCondition(a, b, c, d.input.Parameters.EPSILON)
 		// If either the first point or the last point (or both)
 		// coincides with the vertex, the angle is undefined and
 		// the LIC is not satisfied by those three points
-		if a == b || b == c {
+		if undefined {
 			return false, nil
 		}
-		if (angle < math.Pi - d.input.Parameters.EPSILON || angle > math.Pi + d.input.Parameters.EPSILON) {
+		if satisfied {
 			return true, nil
 		}
 	}
@@ -230,18 +386,20 @@ func (d Decide) Rule2() (bool, error) {
 // There exists at least one set of three consecutive data points
 // that are the vertices of a triangle with area greater than AREA1
 func (d Decide) Rule3() (bool, error) {
-	// (0 ≤ AREA1)
-	if d.input.Parameters.AREA1 < 0 {
-		return false, errors.New("Invalid AREA1")
+	if err := checkRule3(d.input); err != nil {
+		return false, err
 	}
 	for i, p1 := range d.input.Points {
 		if (i >= d.input.NumPoints - 2) {
 			break;
 		}
+		if d.examine(i) {
+			return false, d.ctx.Err()
+		}
 		p2 := d.input.Points[i + 1]
 		p3 := d.input.Points[i + 2]
 
-		area := math.Abs(p1[0] * (p2[1] - p3[1]) + p2[0] * (p3[1] - p1[1]) + p3[0] * (p1[1] - p2[1])) / 2
+		area := triangleArea(p1, p2, p3)
 		if area > d.input.Parameters.AREA1 {
 			return true, nil
 		}
@@ -257,31 +415,41 @@ func (d Decide) Rule3() (bool, error) {
 // For example, the data point (0,0) is in quadrant I, the point (-l,0) is in quadrant II,
 // the point (0,-l) is in quadrant III, the point  (0,1) is in quadrant I and the point (1,0) is in quadrant I.
 func (d Decide) Rule4() (bool, error) {
-	// (2 ≤ Q PTS ≤ NUMPOINTS)
-	if d.input.Parameters.Q_PTS < 2 || d.input.Parameters.Q_PTS > d.input.NumPoints {
-		return false, errors.New("Invalid Q_PTS")
+	if err := checkRule4(d.input); err != nil {
+		return false, err
 	}
-	// (1 ≤ QUADS ≤ 3)
-	if d.input.Parameters.QUADS < 1 || d.input.Parameters.QUADS > 3 {
-		return false, errors.New("Invalid QUADS")
+	// The number of points of the window in each quadrant, updated
+	// with the point that enters and the one that leaves at each step.
+	var quadrants [4]int
+	countUsed := 0
+	add := func(p [2]float64, n int) {
+		q := getQuadranNumber(p)
+		if quadrants[q] == 0 {
+			countUsed++
+		}
+		quadrants[q] += n
+		if quadrants[q] == 0 {
+			countUsed--
+		}
 	}
 	for i := range d.input.Points {
 		if (i > d.input.NumPoints - d.input.Parameters.Q_PTS) {
 			break;
 		}
-		usedQuadrants := make([]bool, 4)
-		for ndx := i; ndx < (i + d.input.Parameters.Q_PTS); ndx++ {
-			usedQuadrants[getQuadranNumber(d.input.Points[ndx])] = true
+		if d.examine(i) {
+			return false, d.ctx.Err()
 		}
-		countUsed := 0
-		for _, v := range usedQuadrants {
-			if (v) {
-				countUsed++;
-				// lie in more than QUADS quadrants
-				if (countUsed > d.input.Parameters.QUADS) {
-					return true, nil
-				}
+		if i == 0 {
+			for ndx := 0; ndx < d.input.Parameters.Q_PTS; ndx++ {
+				add(d.input.Points[ndx], 1)
 			}
+		} else {
+			add(d.input.Points[i - 1], -1)
+			add(d.input.Points[i + d.input.Parameters.Q_PTS - 1], 1)
+		}
+		// lie in more than QUADS quadrants
+		if (countUsed > d.input.Parameters.QUADS) {
+			return true, nil
 		}
 	}
 	return false, nil
@@ -294,6 +462,9 @@ func (d Decide) Rule5() (bool, error) {
 		if (i >= d.input.NumPoints - 1) {
 			break;
 		}
+		if d.examine(i) {
+			return false, d.ctx.Err()
+		}
 		p2 := d.input.Points[i + 1]
 
 		if (p2[0] - p1[0] < 0) {
@@ -316,30 +487,34 @@ func (d Decide) Rule6() (bool, error) {
 	if d.input.NumPoints < 3 {
 		return false, nil
 	}
-	// (3 ≤ N PTS ≤ NUMPOINTS)
-	if d.input.Parameters.N_PTS < 3 || d.input.Parameters.N_PTS > d.input.NumPoints {
-		return false, errors.New("Invalid N_PTS.")
-	}
-	// (0 ≤ DIST)
-	if d.input.Parameters.DIST < 0 {
-		return false, errors.New("Invalid DIST.")
+	if err := checkRule6(d.input); err != nil {
+		return false, err
 	}
 	for i, p1 := range d.input.Points {
 		if (i > d.input.NumPoints - d.input.Parameters.N_PTS) {
 			break;
 		}
+		if d.examine(i) {
+			return false, d.ctx.Err()
+		}
 		p2 := d.input.Points[i + d.input.Parameters.N_PTS - 1]
 
 		dp1p2 := computeDistancePointToPoint(p1, p2)
-		if dp1p2 == 0 {
+		if d.hulls != nil {
+			if d.rule6Hulls(d.hulls.get(), i, p1, p2, dp1p2) {
+				return true, nil
+			}
+		} else if dp1p2 == 0 {
 			for j := i; j < i + d.input.Parameters.N_PTS; j++ {
 				if (computeDistancePointToPoint(d.input.Points[j], p1) > d.input.Parameters.DIST) {
 					return true, nil
 				}
 			}
 		} else {
+			line := computeEquationLine(p1, p2)
+			norm := computeNormLine(line)
 			for j := i + 1; j < i + d.input.Parameters.N_PTS - 1; j++ {
-				if (computeDistancePointToLine(d.input.Points[j], computeEquationLine(p1, p2)) > d.input.Parameters.DIST) {
+				if (computeDistancePointToLineNorm(d.input.Points[j], line, norm) > d.input.Parameters.DIST) {
 					return true, nil
 				}
 			}
@@ -348,6 +523,31 @@ func (d Decide) Rule6() (bool, error) {
 	return false, nil
 }
 
+// rule6Hulls is the search of Rule6 in the set of N PTS points that starts
+// at i, through the hulls of the points. The distance to the line is the
+// absolute value of a linear function of the point, the greatest at one of
+// the two points farthest in the directions of its coefficients. The
+// distance to the coincident point is the greatest at a vertex of a hull.
+func (d Decide) rule6Hulls(t *hullTree, i int, p1 [2]float64, p2 [2]float64, dp1p2 float64) bool {
+	n := d.input.Parameters.N_PTS
+	if dp1p2 == 0 {
+		farther := false
+		t.vertices(i, i + n, func(j int) {
+			farther = farther || computeDistancePointToPoint(d.input.Points[j], p1) > d.input.Parameters.DIST
+		})
+		return farther
+	}
+	line := computeEquationLine(p1, p2)
+	norm := computeNormLine(line)
+	for _, dir := range [][2]float64{{line[0], line[1]}, {-line[0], -line[1]}} {
+		j := t.extreme(i + 1, i + n - 1, dir)
+		if computeDistancePointToLineNorm(d.input.Points[j], line, norm) > d.input.Parameters.DIST {
+			return true
+		}
+	}
+	return false
+}
+
 // There exists at least one set of two data points separated by exactly K PTS consecutive intervening
 // points that are a distance greater than the length, LENGTH1, apart.
 // The condition is not met when NUMPOINTS < 3.
@@ -356,16 +556,17 @@ func (d Decide) Rule7() (bool, error) {
 	if d.input.NumPoints < 3 {
 		return false, nil
 	}
-	// 1 ≤ K PTS ≤ (NUMPOINTS−2)
-	if d.input.Parameters.K_PTS < 1 || d.input.Parameters.K_PTS > d.input.NumPoints - 2 {
-		return false, errors.New("Invalid K_PTS.")
+	if err := checkRule7(d.input); err != nil {
+		return false, err
 	}
-	for i, p1 := range d.input.Points {
+	for i := range d.input.Points {
 		if (i >= d.input.NumPoints - d.input.Parameters.K_PTS - 1) {
 			break;
 		}
-		p2 := d.input.Points[i + d.input.Parameters.K_PTS + 1]
-		if computeDistancePointToPoint(p1, p2) > d.input.Parameters.LENGTH1 {
+		if d.examine(i) {
+			return false, d.ctx.Err()
+		}
+		if d.kDistance(i) > d.input.Parameters.LENGTH1 {
 			return true, nil
 		}
 	}
@@ -380,34 +581,20 @@ func (d Decide) Rule8() (bool, error) {
 	if d.input.NumPoints < 5 {
 		return false, nil
 	}
-	// A PTS+B PTS ≤ (NUMPOINTS−3)
-	if d.input.Parameters.A_PTS + d.input.Parameters.B_PTS > d.input.NumPoints - 3 {
-		return false, errors.New("Invalid A_PTS, B_PTS.")
-	}
-	// 1 ≤ A PTS
-	if d.input.Parameters.A_PTS < 1 {
-		return false, errors.New("Invalid A_PTS.")
-	}
-	// 1 ≤ B PTS
-	if d.input.Parameters.B_PTS < 1 {
-		return false, errors.New("Invalid B_PTS.")
+	if err := checkRule8(d.input); err != nil {
+		return false, err
 	}
 	for i, p1 := range d.input.Points {
 		if (i >= d.input.NumPoints - d.input.Parameters.A_PTS - d.input.Parameters.B_PTS - 2) {
 			break;
 		}
+		if d.examine(i) {
+			return false, d.ctx.Err()
+		}
 		p2 := d.input.Points[i + d.input.Parameters.A_PTS + 1]
 		p3 := d.input.Points[i + d.input.Parameters.A_PTS + d.input.Parameters.B_PTS + 2]
 
-		// center of the 3 points
-		var pc [2]float64
-		pc[0] = (p1[0] + p2[0] + p3[0]) / 3;
-		pc[1] = (p1[1] + p2[1] + p3[1]) / 3;
-
-		r1 := computeDistancePointToPoint(p1, pc)
-		r2 := computeDistancePointToPoint(p2, pc)
-		r3 := computeDistancePointToPoint(p3, pc)
-		if (r1 > d.input.Parameters.RADIUS1 || r2 > d.input.Parameters.RADIUS1 || r3 > d.input.Parameters.RADIUS1) {
+		if outsideCircle(p1, p2, p3, d.input.Parameters.RADIUS1) {
 			return true, nil
 		}
 	}
@@ -426,41 +613,31 @@ func (d Decide) Rule9() (bool, error) {
 	if d.input.NumPoints < 5 {
 		return false, nil
 	}
-	// 1 ≤ C PTS
-	if d.input.Parameters.C_PTS < 1 {
-		return false, errors.New("Invalid C_PTS.")
+	if err := checkRule9(d.input); err != nil {
+		return false, err
 	}
-	// 1 ≤ D PTS
-	if d.input.Parameters.D_PTS < 1 {
-		return false, errors.New("Invalid D_PTS.")
-	}
-	// C PTS+D PTS ≤ NUMPOINTS−3
-	if d.input.Parameters.C_PTS + d.input.Parameters.D_PTS > d.input.NumPoints - 3 {
+	// C PTS+D PTS ≤ NUMPOINTS−3, without overflowing the sum.
+	if d.input.Parameters.C_PTS > d.input.NumPoints - 3 - d.input.Parameters.D_PTS {
 		return false, nil
 	}
 	for i, a := range d.input.Points {
 		if (i >= d.input.NumPoints - d.input.Parameters.C_PTS - d.input.Parameters.D_PTS - 2) {
 			break;
 		}
+		if d.examine(i) {
+			return false, d.ctx.Err()
+		}
 		b := d.input.Points[i + d.input.Parameters.C_PTS + 1]
 		c := d.input.Points[i + d.input.Parameters.C_PTS + d.input.Parameters.D_PTS + 2]
 
-		// http://stackoverflow.com/questions/3486172/angle-between-3-points
-		ab := [2]float64{b[0] - a[0], b[1] - a[1]}
-		cb := [2]float64{b[0] - c[0], b[1] - c[1]}
-
-		dot := (ab[0] * cb[0] + ab[1] * cb[1])
-		cross := (ab[0] * cb[1] - ab[1] * cb[0])
-
-		angle := math.Atan2(cross, dot)
-
+		satisfied, undefined := angleCondition(a, b, c, d.input.Parameters.EPSILON)
 		// If either the first point or the last point (or both)
 		// coincides with the vertex, the angle is undefined and
 		// the LIC is not satisfied by those three points
-		if a == b || b == c {
+		if undefined {
 			return false, nil
 		}
-		if (angle < math.Pi - d.input.Parameters.EPSILON || angle > math.Pi + d.input.Parameters.EPSILON) {
+		if satisfied {
 			return true, nil
 		}
 	}
@@ -476,26 +653,24 @@ func (d Decide) Rule10() (bool, error) {
 	if d.input.NumPoints < 5 {
 		return false, nil
 	}
-	// 1 ≤ E PTS
-	if d.input.Parameters.E_PTS < 1 {
-		return false, errors.New("Invalid E_PTS.")
-	}
-	// 1 ≤ F PTS
-	if d.input.Parameters.F_PTS < 1 {
-		return false, errors.New("Invalid F_PTS.")
+	if err := checkRule10(d.input); err != nil {
+		return false, err
 	}
-	// E PTS+F PTS ≤ NUMPOINTS−3
-	if d.input.Parameters.E_PTS + d.input.Parameters.F_PTS > d.input.NumPoints - 3 {
+	// E PTS+F PTS ≤ NUMPOINTS−3, without overflowing the sum.
+	if d.input.Parameters.E_PTS > d.input.NumPoints - 3 - d.input.Parameters.F_PTS {
 		return false, nil
 	}
 	for i, p1 := range d.input.Points {
 		if (i >= d.input.NumPoints - d.input.Parameters.E_PTS - d.input.Parameters.F_PTS - 2) {
 			break;
 		}
+		if d.examine(i) {
+			return false, d.ctx.Err()
+		}
 		p2 := d.input.Points[i + d.input.Parameters.E_PTS + 1]
 		p3 := d.input.Points[i + d.input.Parameters.E_PTS + d.input.Parameters.F_PTS + 2]
 
-		area := math.Abs((p1[0] * (p2[1] - p3[1]) + p2[0] * (p3[1] - p2[1]) + p3[0] * (p1[1] - p2[1])) / 2)
+		area := triangleArea(p1, p2, p3)
 		if area > d.input.Parameters.AREA1 {
 			return true, nil
 		}
@@ -512,6 +687,9 @@ func (d Decide) Rule11() (bool, error) {
 	if d.input.NumPoints < 3 {
 		return false, nil
 	}
+	if err := checkRule11(d.input); err != nil {
+		return false, err
+	}
 	// 1 ≤ G PTS ≤ NUMPOINTS−2
 	if d.input.Parameters.G_PTS > d.input.NumPoints - 2 {
 		return false, nil
@@ -520,6 +698,9 @@ func (d Decide) Rule11() (bool, error) {
 		if (i >= d.input.NumPoints - d.input.Parameters.G_PTS - 1) {
 			break;
 		}
+		if d.examine(i) {
+			return false, d.ctx.Err()
+		}
 		p2 := d.input.Points[i + d.input.Parameters.G_PTS + 1]
 
 		if (p2[0] - p1[0] < 0) {
@@ -542,18 +723,19 @@ func (d Decide) Rule12() (bool, error) {
 	if d.input.NumPoints < 3 {
 		return false, nil
 	}
-	// 0 ≤ LENGTH2
-	if d.input.Parameters.LENGTH2 < 0 {
-		return false, errors.New("Invalid LENGTH2.")
+	if err := checkRule12(d.input); err != nil {
+		return false, err
 	}
 	cond1 := false
 	cond2 := false
-	for i, p1 := range d.input.Points {
+	for i := range d.input.Points {
 		if (i >= d.input.NumPoints - d.input.Parameters.K_PTS - 1) {
 			break;
 		}
-		p2 := d.input.Points[i + d.input.Parameters.K_PTS + 1]
-		dp1dp2 := computeDistancePointToPoint(p1, p2)
+		if d.examine(i) {
+			return false, d.ctx.Err()
+		}
+		dp1dp2 := d.kDistance(i)
 		if !cond1 && dp1dp2 > d.input.Parameters.LENGTH1 {
 			cond1 = true
 		}
@@ -580,9 +762,8 @@ func (d Decide) Rule13() (bool, error) {
 	if d.input.NumPoints < 5 {
 		return false, nil
 	}
-	// 0 ≤ RADIUS2
-	if d.input.Parameters.RADIUS2 < 0 {
-		return false, errors.New("Invalid RADIUS2.")
+	if err := checkRule13(d.input); err != nil {
+		return false, err
 	}
 	cond1 := false
 	cond2 := false
@@ -590,21 +771,16 @@ func (d Decide) Rule13() (bool, error) {
 		if (i >= d.input.NumPoints - d.input.Parameters.A_PTS - d.input.Parameters.B_PTS - 2) {
 			break;
 		}
+		if d.examine(i) {
+			return false, d.ctx.Err()
+		}
 		p2 := d.input.Points[i + d.input.Parameters.A_PTS + 1]
 		p3 := d.input.Points[i + d.input.Parameters.A_PTS + d.input.Parameters.B_PTS + 2]
 
-		// center of the 3 points
-		var pc [2]float64
-		pc[0] = (p1[0] + p2[0] + p3[0]) / 3;
-		pc[1] = (p1[1] + p2[1] + p3[1]) / 3;
-
-		r1 := computeDistancePointToPoint(p1, pc)
-		r2 := computeDistancePointToPoint(p2, pc)
-		r3 := computeDistancePointToPoint(p3, pc)
-		if (!cond2 && (r1 > d.input.Parameters.RADIUS1 || r2 > d.input.Parameters.RADIUS1 || r3 > d.input.Parameters.RADIUS1)) {
+		if (!cond2 && outsideCircle(p1, p2, p3, d.input.Parameters.RADIUS1)) {
 			cond2 = true
 		}
-		if (!cond1 && (r1 < d.input.Parameters.RADIUS2 && r2 < d.input.Parameters.RADIUS2 && r3 < d.input.Parameters.RADIUS2)) {
+		if (!cond1 && insideCircle(p1, p2, p3, d.input.Parameters.RADIUS2)) {
 			cond1 = true
 		}
 		if cond1 && cond2 {
@@ -627,9 +803,12 @@ func (d Decide) Rule14() (bool, error) {
 	if d.input.NumPoints < 5 {
 		return false, nil
 	}
-	// 0 ≤ AREA2
-	if d.input.Parameters.AREA2 < 0 {
-		return false, errors.New("Invalid AREA2.")
+	if err := checkRule14(d.input); err != nil {
+		return false, err
+	}
+	// E PTS+F PTS ≤ NUMPOINTS−3, without overflowing the sum.
+	if d.input.Parameters.E_PTS > d.input.NumPoints - 3 - d.input.Parameters.F_PTS {
+		return false, nil
 	}
 	cond1 := false
 	cond2 := false
@@ -637,9 +816,12 @@ func (d Decide) Rule14() (bool, error) {
 		if (i >= d.input.NumPoints - d.input.Parameters.E_PTS - d.input.Parameters.F_PTS - 2) {
 			break;
 		}
+		if d.examine(i) {
+			return false, d.ctx.Err()
+		}
 		p2 := d.input.Points[i + d.input.Parameters.E_PTS + 1]
 		p3 := d.input.Points[i + d.input.Parameters.E_PTS + d.input.Parameters.F_PTS + 2]
-		area := math.Abs(p1[0] * (p2[1] - p3[1]) + p2[0] * (p3[1] - p1[1]) + p3[0] * (p1[1] - p2[1])) / 2
+		area := triangleArea(p1, p2, p3)
 		if !cond1 && area > d.input.Parameters.AREA1 {
 			cond1 = true
 		}
@@ -663,6 +845,90 @@ func (d *Decide) isToLaunch() {
 	d.Launch = "YES"
 }
 
+// kDistance returns the distance between the point i and the point
+// separated from it by K_PTS consecutive intervening points.
+func (d Decide) kDistance(i int) float64 {
+	if d.kDistances != nil {
+		return d.kDistances.at(i)
+	}
+	return computeDistancePointToPoint(d.input.Points[i], d.input.Points[i + d.input.Parameters.K_PTS + 1])
+}
+
+// lazyDistances computes the distances between the points i and i + gap
+// the first time one of them is needed, which a lazy evaluation or
+// another evaluation than float may never do. It is safe for concurrent
+// use.
+type lazyDistances struct {
+	once   sync.Once
+	points [][2]float64
+	gap    int
+	values []float64
+}
+
+func (l *lazyDistances) at(i int) float64 {
+	l.once.Do(func() {
+		l.values = distances(l.points, l.gap)
+	})
+	return l.values[i]
+}
+
+// distances returns the distances between the points i and i + gap.
+func distances(points [][2]float64, gap int) []float64 {
+	if gap >= len(points) {
+		return nil
+	}
+	values := make([]float64, len(points) - gap)
+	for i := range values {
+		values[i] = computeDistancePointToPoint(points[i], points[i + gap])
+	}
+	return values
+}
+
+// outsideCircle reports whether the three points cannot be contained
+// within or on a circle of the radius.
+func outsideCircle(p1 [2]float64, p2 [2]float64, p3 [2]float64, radius float64) bool {
+	pc := centroid(p1, p2, p3)
+	return computeDistancePointToPoint(p1, pc) > radius || computeDistancePointToPoint(p2, pc) > radius || computeDistancePointToPoint(p3, pc) > radius
+}
+
+// insideCircle reports whether the three points are contained within
+// a circle of the radius.
+func insideCircle(p1 [2]float64, p2 [2]float64, p3 [2]float64, radius float64) bool {
+	pc := centroid(p1, p2, p3)
+	return computeDistancePointToPoint(p1, pc) < radius && computeDistancePointToPoint(p2, pc) < radius && computeDistancePointToPoint(p3, pc) < radius
+}
+
+// centroid returns the center of the 3 points.
+func centroid(p1 [2]float64, p2 [2]float64, p3 [2]float64) [2]float64 {
+	var pc [2]float64
+	pc[0] = (p1[0] + p2[0] + p3[0]) / 3;
+	pc[1] = (p1[1] + p2[1] + p3[1]) / 3;
+	return pc
+}
+
+// angleCondition reports whether the angle of a, b and c, with b as
+// vertex, is such that angle < (PI−EPSILON) or angle > (PI+EPSILON), and
+// whether the angle is undefined because a or c coincides with b.
+func angleCondition(a [2]float64, b [2]float64, c [2]float64, epsilon float64) (bool, bool) {
+	if a == b || b == c {
+		return false, true
+	}
+	// http://stackoverflow.com/questions/3486172/angle-between-3-points
+	ab := [2]float64{b[0] - a[0], b[1] - a[1]}
+	cb := [2]float64{b[0] - c[0], b[1] - c[1]}
+
+	dot := (ab[0] * cb[0] + ab[1] * cb[1])
+	cross := (ab[0] * cb[1] - ab[1] * cb[0])
+
+	angle := math.Atan2(cross, dot)
+	return angle < math.Pi - epsilon || angle > math.Pi + epsilon, false
+}
+
+// triangleArea returns the area of the triangle of the three points.
+func triangleArea(p1 [2]float64, p2 [2]float64, p3 [2]float64) float64 {
+	return math.Abs(p1[0] * (p2[1] - p3[1]) + p2[0] * (p3[1] - p1[1]) + p3[0] * (p1[1] - p2[1])) / 2
+}
+
 func computeEquationLine(p1 [2]float64, p2 [2]float64) [3]float64 {
 	var equation [3]float64
 
@@ -674,7 +940,17 @@ func computeEquationLine(p1 [2]float64, p2 [2]float64) [3]float64 {
 }
 
 func computeDistancePointToLine(p1 [2]float64, equationLine [3]float64) float64 {
-	return math.Abs(equationLine[0] * p1[0] + equationLine[1] * p1[1] + equationLine[2]) / math.Sqrt(math.Pow(equationLine[0], 2) + math.Pow(equationLine[1], 2));
+	return computeDistancePointToLineNorm(p1, equationLine, computeNormLine(equationLine))
+}
+
+// computeNormLine returns the norm of the normal vector of the line, to
+// compute the distance of many points to the same line.
+func computeNormLine(equationLine [3]float64) float64 {
+	return math.Sqrt(math.Pow(equationLine[0], 2) + math.Pow(equationLine[1], 2));
+}
+
+func computeDistancePointToLineNorm(p1 [2]float64, equationLine [3]float64, norm float64) float64 {
+	return math.Abs(equationLine[0] * p1[0] + equationLine[1] * p1[1] + equationLine[2]) / norm;
 }
 
 func computeDistancePointToPoint(p1 [2]float64, p2 [2]float64) float64 {
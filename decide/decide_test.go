@@ -47,6 +47,41 @@ func TestDecide_Decide(t *testing.T) {
 	}
 
 }
+
+func TestPerformPUM(t *testing.T) {
+	decide := Decide{}
+	var row [NB_LIC]Command
+	row[0] = ANDD
+	row[1] = "ORR"
+	row[2] = "NOTUSED"
+	decide.input.LCM = map[string][NB_LIC]Command{"0": row}
+	decide.CMV[0] = true
+	decide.performPUM()
+	if !decide.PUM[0][0] {
+		t.Error("Expected ANDD of two true LICs to be true")
+		return
+	}
+	if !decide.PUM[0][1] {
+		t.Error("Expected ORR of a true and a false LIC to be true")
+		return
+	}
+	if !decide.PUM[0][2] {
+		t.Error("Expected NOTUSED to be true")
+		return
+	}
+
+	decide.CMV[0] = false
+	decide.performPUM()
+	if decide.PUM[0][1] {
+		t.Error("Expected ORR of two false LICs to be false")
+		return
+	}
+	if !decide.PUM[0][2] {
+		t.Error("Expected NOTUSED to be true whatever the LICs")
+		return
+	}
+}
+
 func TestRule0(t *testing.T) {
 	decide := Decide{}
 	points := make([][2]float64, 2)
@@ -0,0 +1,57 @@
+package decide
+
+import "fmt"
+
+// Difference is a single entry that differs between two decisions.
+type Difference struct {
+	Field string      `json:"FIELD"`
+	A     interface{} `json:"A"`
+	B     interface{} `json:"B"`
+}
+
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: %v != %v", d.Field, d.A, d.B)
+}
+
+// Compare lists the entries of LAUNCH, CMV, PUM and FUV that differ
+// between a and b. It returns nil when both decisions are identical. The
+// CMV and PUM entries of a LIC that a lazy evaluation of a or b did not
+// evaluate are not compared, see Evaluated.
+func Compare(a Decide, b Decide) []Difference {
+	var diffs []Difference
+	if a.Launch != b.Launch {
+		diffs = append(diffs, Difference{"LAUNCH", a.Launch, b.Launch})
+	}
+	evaluated := func(lic int) bool {
+		return a.Evaluated(lic) && b.Evaluated(lic)
+	}
+	for i := 0; i < NB_LIC; i++ {
+		if evaluated(i) && a.CMV[i] != b.CMV[i] {
+			diffs = append(diffs, Difference{fmt.Sprintf("CMV[%d]", i), a.CMV[i], b.CMV[i]})
+		}
+	}
+	for i := 0; i < NB_LIC; i++ {
+		for j := 0; j < NB_LIC; j++ {
+			if evaluated(i) && evaluated(j) && a.PUM[i][j] != b.PUM[i][j] {
+				diffs = append(diffs, Difference{fmt.Sprintf("PUM[%d][%d]", i, j), a.PUM[i][j], b.PUM[i][j]})
+			}
+		}
+	}
+	for i := 0; i < NB_LIC; i++ {
+		if a.FUV[i] != b.FUV[i] {
+			diffs = append(diffs, Difference{fmt.Sprintf("FUV[%d]", i), a.FUV[i], b.FUV[i]})
+		}
+	}
+	return diffs
+}
+
+// Evaluated reports whether the LIC lic was evaluated, that is it is not
+// listed in NotEvaluated. Its CMV entry is false otherwise.
+func (d Decide) Evaluated(lic int) bool {
+	for _, i := range d.NotEvaluated {
+		if i == lic {
+			return false
+		}
+	}
+	return true
+}
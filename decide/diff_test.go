@@ -0,0 +1,59 @@
+package decide
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCompare(t *testing.T) {
+	a := Decide{}
+	if err := a.Decide(Generate(4)); err != nil {
+		t.Error(err)
+		return
+	}
+	b := a
+	if diffs := Compare(a, b); diffs != nil {
+		t.Error("Expected no difference", diffs)
+		return
+	}
+
+	b.CMV[2] = !b.CMV[2]
+	b.PUM[1][3] = !b.PUM[1][3]
+	diffs := Compare(a, b)
+	if len(diffs) != 2 {
+		t.Error("Expected two differences", diffs)
+		return
+	}
+	if diffs[0].Field != "CMV[2]" || diffs[1].Field != "PUM[1][3]" {
+		t.Error("Unexpected differences", diffs)
+		return
+	}
+}
+
+func TestCompareLazy(t *testing.T) {
+	input := Generate(4)
+	input.PUV = [NB_LIC]bool{}
+	input.PUV[3] = true
+	a, _ := (&Engine{}).Decide(context.Background(), input)
+	b, err := (&Engine{Lazy: true}).Decide(context.Background(), input)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(b.NotEvaluated) == 0 {
+		t.Fatal("Expected a LIC not to be evaluated")
+	}
+	if diffs := Compare(a, b); diffs != nil {
+		t.Error("Expected the LICs not evaluated to be ignored, got", diffs)
+		return
+	}
+	if err := b.SetProvenance("-", time.Unix(0, 0)); err != nil {
+		t.Error(err)
+		return
+	}
+	if !b.Provenance.Lazy || !b.Provenance.Engine().Lazy {
+		t.Error("Expected the lazy evaluation in the provenance, got", b.Provenance)
+		return
+	}
+}
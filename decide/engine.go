@@ -0,0 +1,111 @@
+package decide
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// Observer is notified of every evaluation of an Engine. Its methods
+// may be called concurrently by concurrent evaluations.
+type Observer interface {
+	// ObserveRule is called after the evaluation of each LIC.
+	ObserveRule(lic int, value bool, elapsed time.Duration, err error)
+	// ObserveDecision is called once per input, with the error of
+	// the evaluation if any.
+	ObserveDecision(input INPUT, d *Decide, err error)
+}
+
+// Engine evaluates inputs. The zero Engine evaluates them like
+// Decide.Decide.
+type Engine struct {
+	// Observer, when set, is notified of every evaluation.
+	Observer Observer
+	// Logger receives the events of the evaluations: rules at debug
+	// level, decisions at info level and rejected inputs at warn level.
+	// Nothing is logged when it is nil.
+	Logger *slog.Logger
+	// Trace attaches to every result the Trace of its derivation.
+	Trace bool
+	// MaxPoints is the maximum NUMPOINTS of an input, DefaultMaxPoints
+	// when 0.
+	MaxPoints int
+	// Bounds rejects the inputs with a point out of its bounding box. The
+	// coordinates of the points are only required to be finite when it
+	// is nil.
+	Bounds *Bounds
+	// Concurrency is the number of LICs evaluated concurrently. The LICs
+	// are evaluated one after the other when it is 0 or 1. The results
+	// do not depend on it, but a concurrent evaluation reports the errors
+	// of every rule in a RuleErrors instead of stopping at the first.
+	Concurrency int
+	// Evaluation is the evaluation of the LICs, EvaluationFloat when
+	// empty.
+	Evaluation Evaluation
+	// Lazy skips the LICs that cannot change the launch decision for the
+	// LCM and PUV of the input, see Relevant. Their parameters are still
+	// checked.
+	Lazy bool
+}
+
+func (e *Engine) mode() Mode {
+	if e.Concurrency > 1 {
+		return ModeConcurrent
+	}
+	return ModeSequential
+}
+
+func (e *Engine) evaluation() Evaluation {
+	if e.Evaluation == "" {
+		return EvaluationFloat
+	}
+	return e.Evaluation
+}
+
+// DefaultMaxPoints is the maximum NUMPOINTS of the specification.
+const DefaultMaxPoints = 100
+
+func (e *Engine) maxPoints() int {
+	if e.MaxPoints == 0 {
+		return DefaultMaxPoints
+	}
+	return e.MaxPoints
+}
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func (e *Engine) logger() *slog.Logger {
+	if e.Logger == nil {
+		return discard
+	}
+	return e.Logger
+}
+
+// Decide evaluates input and returns its result. It gives up between
+// two LICs once ctx is done, returning the error of ctx.
+func (e *Engine) Decide(ctx context.Context, input INPUT) (Decide, error) {
+	d := Decide{}
+	err := e.decide(ctx, &d, input)
+	return d, err
+}
+
+func (e *Engine) decide(ctx context.Context, d *Decide, input INPUT) error {
+	start := time.Now()
+	err := d.evaluate(ctx, e, input)
+	if e.Observer != nil {
+		e.Observer.ObserveDecision(input, d, err)
+	}
+	if err != nil {
+		var field string
+		var validation *ValidationError
+		if errors.As(err, &validation) {
+			field = validation.Field
+		}
+		e.logger().Warn("input rejected", "numpoints", input.NumPoints, "field", field, "error", err)
+		return err
+	}
+	e.logger().Info("decision", "launch", d.Launch, "numpoints", input.NumPoints, "elapsed", time.Since(start))
+	return nil
+}
@@ -0,0 +1,181 @@
+package decide
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recorder struct {
+	rules     []int
+	decisions int
+	err       error
+}
+
+func (r *recorder) ObserveRule(lic int, value bool, elapsed time.Duration, err error) {
+	r.rules = append(r.rules, lic)
+}
+
+func (r *recorder) ObserveDecision(input INPUT, d *Decide, err error) {
+	r.decisions++
+	r.err = err
+}
+
+func TestEngineObserver(t *testing.T) {
+	r := &recorder{}
+	e := Engine{Observer: r}
+	input := Generate(21)
+	d, err := e.Decide(context.Background(), input)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expected := Decide{}
+	expected.Decide(input)
+	if diffs := Compare(expected, d); diffs != nil {
+		t.Error("Unexpected differences", diffs)
+		return
+	}
+	if len(r.rules) != NB_LIC || r.decisions != 1 || r.err != nil {
+		t.Error("Unexpected observations", r)
+		return
+	}
+
+	input.Parameters.QUADS = 0
+	if _, err := e.Decide(context.Background(), input); err == nil {
+		t.Error("Invalid QUADS expected")
+		return
+	}
+	if r.decisions != 2 || r.err == nil {
+		t.Error("Expected the error to be observed", r)
+		return
+	}
+}
+
+func TestEngineContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var e Engine
+	if _, err := e.Decide(ctx, Generate(22)); err != context.Canceled {
+		t.Error("Expected context.Canceled, got", err)
+		return
+	}
+}
+
+// TestRulesContext checks that the rules stop scanning the sets of points
+// of a large input once the context of the decision is done, rather than
+// only between two LICs.
+func TestRulesContext(t *testing.T) {
+	input := largeInput(10000)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, evaluation := range Evaluations {
+		g, err := evaluation.geometry()
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := Decide{input: input, ctx: ctx}
+		if evaluation != EvaluationFloat {
+			d.geometry = g
+		}
+		for lic := 0; lic < NB_LIC; lic++ {
+			if _, err := d.Rule(lic); !errors.Is(err, context.Canceled) {
+				t.Error("Expected the", evaluation, "evaluation of LIC", lic, "to stop with context.Canceled, got", err)
+				return
+			}
+		}
+	}
+}
+
+func TestEngineTrace(t *testing.T) {
+	e := Engine{Trace: true}
+	input := Generate(23)
+	input.Points[0] = [2]float64{0, 0}
+	input.Points[1] = [2]float64{1e6, 0}
+	input.Parameters.LENGTH1 = 10
+	d, err := e.Decide(context.Background(), input)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if d.Trace == nil || len(d.Trace.Rules) != NB_LIC || len(d.Trace.PUM) != NB_LIC * NB_LIC || len(d.Trace.FUV) != NB_LIC {
+		t.Error("Incomplete trace", d.Trace)
+		return
+	}
+	rule0 := d.Trace.Rules[0]
+	if !rule0.Value || rule0.Windows != 1 || rule0.ExitIndex != 0 {
+		t.Error("Expected Rule0 to stop at the first window", rule0)
+		return
+	}
+	for _, rule := range d.Trace.Rules {
+		if rule.Value != d.CMV[rule.LIC] || rule.End.Before(rule.Start) {
+			t.Error("Inconsistent rule trace", rule)
+			return
+		}
+	}
+	for _, step := range d.Trace.FUV {
+		if step.Value != d.FUV[step.I] || step.Value != (step.Blocking == -1) {
+			t.Error("Inconsistent FUV step", step)
+			return
+		}
+	}
+
+	e.Trace = false
+	d, _ = e.Decide(context.Background(), input)
+	if d.Trace != nil {
+		t.Error("Expected no trace")
+		return
+	}
+}
+
+func TestEngineLogger(t *testing.T) {
+	var buffer bytes.Buffer
+	e := Engine{Logger: slog.New(slog.NewJSONHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))}
+	if _, err := e.Decide(context.Background(), Generate(24)); err != nil {
+		t.Error(err)
+		return
+	}
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != NB_LIC + 1 {
+		t.Error("Expected one event per rule and one per decision, got", len(lines))
+		return
+	}
+	var event map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[NB_LIC]), &event); err != nil {
+		t.Error(err)
+		return
+	}
+	if event["msg"] != "decision" || event["launch"] == nil {
+		t.Error("Unexpected event", event)
+		return
+	}
+}
+
+func TestEngineMaxPoints(t *testing.T) {
+	config := DefaultGeneratorConfig()
+	config.NumPoints = Range{1000, 1000}
+	config.MaxPoints = 1000
+	input, err := GenerateWith(1, config)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := Validate(input); err == nil {
+		t.Error("Expected more than 100 points to be rejected by default")
+		return
+	}
+	e := Engine{MaxPoints: 1000}
+	if err := e.Validate(input); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := e.Decide(context.Background(), input); err != nil {
+		t.Error(err)
+		return
+	}
+}
@@ -0,0 +1,529 @@
+package decide
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Evaluation is the arithmetic and the reading of the specification used
+// to compare the points with the parameters.
+type Evaluation string
+
+const (
+	// EvaluationFloat is the evaluation of Rule0 to Rule14: float64
+	// arithmetic and strict comparisons.
+	EvaluationFloat Evaluation = "float"
+	// EvaluationTolerant compares the quantities with the thresholds with
+	// a relative Tolerance: a quantity within the tolerance of its
+	// threshold is neither greater nor lower.
+	EvaluationTolerant Evaluation = "tolerant"
+	// EvaluationExact evaluates the same formulas as EvaluationFloat with
+	// exact rational arithmetic on the coordinates and parameters. Only
+	// the cosine of EPSILON is rounded.
+	EvaluationExact Evaluation = "exact"
+	// EvaluationStrict follows the specification where EvaluationFloat
+	// departs from it: the circles are the smallest enclosing circles
+	// instead of the circles around the centroid, the angles are measured
+	// between 0 and PI whatever the direction of the turn, the distance
+	// to a line uses the line through both points, a set of points with
+	// an undefined angle does not stop the search, and RADIUS2 contains
+	// the points on the circle.
+	EvaluationStrict Evaluation = "strict"
+)
+
+// Evaluations lists the evaluations, EvaluationFloat first.
+var Evaluations = []Evaluation{EvaluationFloat, EvaluationTolerant, EvaluationExact, EvaluationStrict}
+
+// Tolerance is the relative tolerance of EvaluationTolerant.
+const Tolerance = 1e-9
+
+// geometry returns the comparisons of the evaluation.
+func (e Evaluation) geometry() (geometry, error) {
+	switch e {
+	case EvaluationFloat:
+		return floatGeometry{}, nil
+	case EvaluationTolerant:
+		return tolerantGeometry{}, nil
+	case EvaluationExact:
+		return exactGeometry{}, nil
+	case EvaluationStrict:
+		return strictGeometry{}, nil
+	}
+	return nil, fmt.Errorf("unknown evaluation %q", e)
+}
+
+// geometry compares the quantities measured on the points of a set with
+// the parameters.
+type geometry interface {
+	// longer reports whether p and q are a distance greater than length
+	// apart.
+	longer(p [2]float64, q [2]float64, length float64) bool
+	// shorter reports whether p and q are a distance less than length
+	// apart.
+	shorter(p [2]float64, q [2]float64, length float64) bool
+	// outside reports whether the three points cannot be contained within
+	// or on a circle of the radius.
+	outside(p1 [2]float64, p2 [2]float64, p3 [2]float64, radius float64) bool
+	// inside reports whether the three points can be contained in a
+	// circle of the radius.
+	inside(p1 [2]float64, p2 [2]float64, p3 [2]float64, radius float64) bool
+	// angle is like angleCondition.
+	angle(a [2]float64, b [2]float64, c [2]float64, epsilon float64) (bool, bool)
+	// larger reports whether the triangle of the points has an area
+	// greater than area.
+	larger(p1 [2]float64, p2 [2]float64, p3 [2]float64, area float64) bool
+	// smaller reports whether the triangle of the points has an area less
+	// than area.
+	smaller(p1 [2]float64, p2 [2]float64, p3 [2]float64, area float64) bool
+	// coincident reports whether p and q are the same point.
+	coincident(p [2]float64, q [2]float64) bool
+	// farFromLine reports whether p lies a distance greater than dist
+	// from the line joining p1 and p2.
+	farFromLine(p [2]float64, p1 [2]float64, p2 [2]float64, dist float64) bool
+	// decreasing reports whether X[q] - X[p] < 0.
+	decreasing(p [2]float64, q [2]float64) bool
+}
+
+// floatGeometry is the geometry of Rule0 to Rule14.
+type floatGeometry struct{}
+
+func (floatGeometry) longer(p [2]float64, q [2]float64, length float64) bool {
+	return computeDistancePointToPoint(p, q) > length
+}
+
+func (floatGeometry) shorter(p [2]float64, q [2]float64, length float64) bool {
+	return computeDistancePointToPoint(p, q) < length
+}
+
+func (floatGeometry) outside(p1 [2]float64, p2 [2]float64, p3 [2]float64, radius float64) bool {
+	return outsideCircle(p1, p2, p3, radius)
+}
+
+func (floatGeometry) inside(p1 [2]float64, p2 [2]float64, p3 [2]float64, radius float64) bool {
+	return insideCircle(p1, p2, p3, radius)
+}
+
+func (floatGeometry) angle(a [2]float64, b [2]float64, c [2]float64, epsilon float64) (bool, bool) {
+	return angleCondition(a, b, c, epsilon)
+}
+
+func (floatGeometry) larger(p1 [2]float64, p2 [2]float64, p3 [2]float64, area float64) bool {
+	return triangleArea(p1, p2, p3) > area
+}
+
+func (floatGeometry) smaller(p1 [2]float64, p2 [2]float64, p3 [2]float64, area float64) bool {
+	return triangleArea(p1, p2, p3) < area
+}
+
+func (floatGeometry) coincident(p [2]float64, q [2]float64) bool {
+	return computeDistancePointToPoint(p, q) == 0
+}
+
+func (floatGeometry) farFromLine(p [2]float64, p1 [2]float64, p2 [2]float64, dist float64) bool {
+	line := computeEquationLine(p1, p2)
+	return computeDistancePointToLineNorm(p, line, computeNormLine(line)) > dist
+}
+
+func (floatGeometry) decreasing(p [2]float64, q [2]float64) bool {
+	return q[0] - p[0] < 0
+}
+
+// centroidRadius returns the largest distance of the points to their
+// centroid, the radius of the circle of outsideCircle and insideCircle.
+func centroidRadius(p1 [2]float64, p2 [2]float64, p3 [2]float64) float64 {
+	pc := centroid(p1, p2, p3)
+	return math.Max(computeDistancePointToPoint(p1, pc), math.Max(computeDistancePointToPoint(p2, pc), computeDistancePointToPoint(p3, pc)))
+}
+
+// signedAngle returns the angle of angleCondition, between -PI and PI.
+func signedAngle(a [2]float64, b [2]float64, c [2]float64) float64 {
+	ab := [2]float64{b[0] - a[0], b[1] - a[1]}
+	cb := [2]float64{b[0] - c[0], b[1] - c[1]}
+	return math.Atan2(ab[0] * cb[1] - ab[1] * cb[0], ab[0] * cb[0] + ab[1] * cb[1])
+}
+
+func lineDistance(p [2]float64, p1 [2]float64, p2 [2]float64) float64 {
+	line := computeEquationLine(p1, p2)
+	return computeDistancePointToLineNorm(p, line, computeNormLine(line))
+}
+
+// gap returns the distance between a quantity and its threshold relative
+// to their magnitude, or to 1 for small values.
+func gap(x float64, threshold float64) float64 {
+	return math.Abs(x - threshold) / math.Max(1, math.Max(math.Abs(x), math.Abs(threshold)))
+}
+
+func greater(x float64, threshold float64) bool {
+	return x > threshold && gap(x, threshold) > Tolerance
+}
+
+func less(x float64, threshold float64) bool {
+	return x < threshold && gap(x, threshold) > Tolerance
+}
+
+// tolerantGeometry measures the quantities of floatGeometry and compares
+// them with a Tolerance.
+type tolerantGeometry struct{}
+
+func (tolerantGeometry) longer(p [2]float64, q [2]float64, length float64) bool {
+	return greater(computeDistancePointToPoint(p, q), length)
+}
+
+func (tolerantGeometry) shorter(p [2]float64, q [2]float64, length float64) bool {
+	return less(computeDistancePointToPoint(p, q), length)
+}
+
+func (tolerantGeometry) outside(p1 [2]float64, p2 [2]float64, p3 [2]float64, radius float64) bool {
+	return greater(centroidRadius(p1, p2, p3), radius)
+}
+
+func (tolerantGeometry) inside(p1 [2]float64, p2 [2]float64, p3 [2]float64, radius float64) bool {
+	return less(centroidRadius(p1, p2, p3), radius)
+}
+
+func (tolerantGeometry) angle(a [2]float64, b [2]float64, c [2]float64, epsilon float64) (bool, bool) {
+	if a == b || b == c {
+		return false, true
+	}
+	angle := signedAngle(a, b, c)
+	return less(angle, math.Pi - epsilon) || greater(angle, math.Pi + epsilon), false
+}
+
+func (tolerantGeometry) larger(p1 [2]float64, p2 [2]float64, p3 [2]float64, area float64) bool {
+	return greater(triangleArea(p1, p2, p3), area)
+}
+
+func (tolerantGeometry) smaller(p1 [2]float64, p2 [2]float64, p3 [2]float64, area float64) bool {
+	return less(triangleArea(p1, p2, p3), area)
+}
+
+func (tolerantGeometry) coincident(p [2]float64, q [2]float64) bool {
+	return !greater(computeDistancePointToPoint(p, q), 0)
+}
+
+func (tolerantGeometry) farFromLine(p [2]float64, p1 [2]float64, p2 [2]float64, dist float64) bool {
+	return greater(lineDistance(p, p1, p2), dist)
+}
+
+func (tolerantGeometry) decreasing(p [2]float64, q [2]float64) bool {
+	return less(q[0], p[0])
+}
+
+// strictGeometry is the float geometry of the specification.
+type strictGeometry struct {
+	floatGeometry
+}
+
+// enclosingRadius returns the radius of the smallest circle that contains
+// the three points: half the longest side when the triangle is not acute,
+// its circumradius otherwise.
+func enclosingRadius(p1 [2]float64, p2 [2]float64, p3 [2]float64) float64 {
+	sides := [3]float64{
+		computeDistancePointToPoint(p2, p3),
+		computeDistancePointToPoint(p1, p3),
+		computeDistancePointToPoint(p1, p2),
+	}
+	longest := math.Max(sides[0], math.Max(sides[1], sides[2]))
+	squares := sides[0] * sides[0] + sides[1] * sides[1] + sides[2] * sides[2]
+	area := triangleArea(p1, p2, p3)
+	if 2 * longest * longest >= squares || area == 0 {
+		return longest / 2
+	}
+	return sides[0] * sides[1] * sides[2] / (4 * area)
+}
+
+func (strictGeometry) outside(p1 [2]float64, p2 [2]float64, p3 [2]float64, radius float64) bool {
+	return enclosingRadius(p1, p2, p3) > radius
+}
+
+func (strictGeometry) inside(p1 [2]float64, p2 [2]float64, p3 [2]float64, radius float64) bool {
+	return enclosingRadius(p1, p2, p3) <= radius
+}
+
+// angle does not report undefined angles: the set of points does not
+// satisfy the LIC, and the search goes on.
+func (strictGeometry) angle(a [2]float64, b [2]float64, c [2]float64, epsilon float64) (bool, bool) {
+	if a == b || b == c {
+		return false, false
+	}
+	return math.Abs(signedAngle(a, b, c)) < math.Pi - epsilon, false
+}
+
+func (strictGeometry) farFromLine(p [2]float64, p1 [2]float64, p2 [2]float64, dist float64) bool {
+	cross := (p2[0] - p1[0]) * (p[1] - p1[1]) - (p2[1] - p1[1]) * (p[0] - p1[0])
+	return math.Abs(cross) / computeDistancePointToPoint(p1, p2) > dist
+}
+
+// exactGeometry evaluates the formulas of floatGeometry on rationals. The
+// square roots are avoided by comparing squares.
+type exactGeometry struct{}
+
+func rat(f float64) *big.Rat {
+	return new(big.Rat).SetFloat64(f)
+}
+
+func add(x *big.Rat, y *big.Rat) *big.Rat {
+	return new(big.Rat).Add(x, y)
+}
+
+func sub(x *big.Rat, y *big.Rat) *big.Rat {
+	return new(big.Rat).Sub(x, y)
+}
+
+func mul(x *big.Rat, y *big.Rat) *big.Rat {
+	return new(big.Rat).Mul(x, y)
+}
+
+// squaredDistance returns the squared distance between p and q.
+func squaredDistance(p [2]*big.Rat, q [2]*big.Rat) *big.Rat {
+	dx := sub(p[0], q[0])
+	dy := sub(p[1], q[1])
+	return add(mul(dx, dx), mul(dy, dy))
+}
+
+func exact(p [2]float64) [2]*big.Rat {
+	return [2]*big.Rat{rat(p[0]), rat(p[1])}
+}
+
+func (exactGeometry) compareDistance(p [2]float64, q [2]float64, length float64) int {
+	l := rat(length)
+	return squaredDistance(exact(p), exact(q)).Cmp(mul(l, l))
+}
+
+func (g exactGeometry) longer(p [2]float64, q [2]float64, length float64) bool {
+	return g.compareDistance(p, q, length) > 0
+}
+
+func (g exactGeometry) shorter(p [2]float64, q [2]float64, length float64) bool {
+	return g.compareDistance(p, q, length) < 0
+}
+
+// compareCentroid compares with the radius the distance of each point to
+// the centroid of the points.
+func (exactGeometry) compareCentroid(p1 [2]float64, p2 [2]float64, p3 [2]float64, radius float64) [3]int {
+	points := [3][2]*big.Rat{exact(p1), exact(p2), exact(p3)}
+	third := big.NewRat(1, 3)
+	var pc [2]*big.Rat
+	for k := range pc {
+		pc[k] = mul(add(add(points[0][k], points[1][k]), points[2][k]), third)
+	}
+	r := rat(radius)
+	r2 := mul(r, r)
+	var c [3]int
+	for i, p := range points {
+		c[i] = squaredDistance(p, pc).Cmp(r2)
+	}
+	return c
+}
+
+func (g exactGeometry) outside(p1 [2]float64, p2 [2]float64, p3 [2]float64, radius float64) bool {
+	c := g.compareCentroid(p1, p2, p3, radius)
+	return c[0] > 0 || c[1] > 0 || c[2] > 0
+}
+
+func (g exactGeometry) inside(p1 [2]float64, p2 [2]float64, p3 [2]float64, radius float64) bool {
+	c := g.compareCentroid(p1, p2, p3, radius)
+	return c[0] < 0 && c[1] < 0 && c[2] < 0
+}
+
+// angle decides angle < PI-EPSILON, the only condition the angle of
+// angleCondition can meet since it is at most PI: on the cosines,
+// dot > -cos(EPSILON)·|ab|·|cb| when the angle is positive.
+func (exactGeometry) angle(a [2]float64, b [2]float64, c [2]float64, epsilon float64) (bool, bool) {
+	if a == b || b == c {
+		return false, true
+	}
+	pa, pb, pc := exact(a), exact(b), exact(c)
+	ab := [2]*big.Rat{sub(pb[0], pa[0]), sub(pb[1], pa[1])}
+	cb := [2]*big.Rat{sub(pb[0], pc[0]), sub(pb[1], pc[1])}
+	dot := add(mul(ab[0], cb[0]), mul(ab[1], cb[1]))
+	cross := sub(mul(ab[0], cb[1]), mul(ab[1], cb[0]))
+	switch cross.Sign() {
+	case -1:
+		// A negative angle.
+		return true, false
+	case 0:
+		// An angle of 0, or of PI when the vectors are opposite.
+		return dot.Sign() >= 0, false
+	}
+	k := rat(-math.Cos(epsilon))
+	lengths := mul(squaredDistance(pa, pb), squaredDistance(pc, pb))
+	bound := mul(mul(k, k), lengths)
+	dot2 := mul(dot, dot)
+	if k.Sign() >= 0 {
+		return dot.Sign() > 0 && dot2.Cmp(bound) > 0, false
+	}
+	return dot.Sign() >= 0 || dot2.Cmp(bound) < 0, false
+}
+
+func (exactGeometry) compareArea(p1 [2]float64, p2 [2]float64, p3 [2]float64, area float64) int {
+	a, b, c := exact(p1), exact(p2), exact(p3)
+	twice := add(add(mul(a[0], sub(b[1], c[1])), mul(b[0], sub(c[1], a[1]))), mul(c[0], sub(a[1], b[1])))
+	twice.Abs(twice)
+	return twice.Cmp(mul(big.NewRat(2, 1), rat(area)))
+}
+
+func (g exactGeometry) larger(p1 [2]float64, p2 [2]float64, p3 [2]float64, area float64) bool {
+	return g.compareArea(p1, p2, p3, area) > 0
+}
+
+func (g exactGeometry) smaller(p1 [2]float64, p2 [2]float64, p3 [2]float64, area float64) bool {
+	return g.compareArea(p1, p2, p3, area) < 0
+}
+
+func (exactGeometry) coincident(p [2]float64, q [2]float64) bool {
+	return p == q
+}
+
+// farFromLine uses the line of computeEquationLine.
+func (exactGeometry) farFromLine(p [2]float64, p1 [2]float64, p2 [2]float64, dist float64) bool {
+	a, b, q := exact(p1), exact(p2), exact(p)
+	line := [3]*big.Rat{sub(a[1], b[1]), sub(a[0], b[0]), sub(mul(a[0], b[1]), mul(b[0], a[1]))}
+	value := add(add(mul(line[0], q[0]), mul(line[1], q[1])), line[2])
+	d := rat(dist)
+	norm := add(mul(line[0], line[0]), mul(line[1], line[1]))
+	return mul(value, value).Cmp(mul(mul(d, d), norm)) > 0
+}
+
+func (exactGeometry) decreasing(p [2]float64, q [2]float64) bool {
+	return q[0] < p[0]
+}
+
+// marginGeometry is floatGeometry that records the smallest gap between
+// the quantities it compares and their thresholds.
+type marginGeometry struct {
+	floatGeometry
+	margin *float64
+}
+
+func (g marginGeometry) record(x float64, threshold float64) {
+	*g.margin = math.Min(*g.margin, gap(x, threshold))
+}
+
+func (g marginGeometry) longer(p [2]float64, q [2]float64, length float64) bool {
+	g.record(computeDistancePointToPoint(p, q), length)
+	return g.floatGeometry.longer(p, q, length)
+}
+
+func (g marginGeometry) shorter(p [2]float64, q [2]float64, length float64) bool {
+	g.record(computeDistancePointToPoint(p, q), length)
+	return g.floatGeometry.shorter(p, q, length)
+}
+
+func (g marginGeometry) outside(p1 [2]float64, p2 [2]float64, p3 [2]float64, radius float64) bool {
+	g.record(centroidRadius(p1, p2, p3), radius)
+	return g.floatGeometry.outside(p1, p2, p3, radius)
+}
+
+func (g marginGeometry) inside(p1 [2]float64, p2 [2]float64, p3 [2]float64, radius float64) bool {
+	g.record(centroidRadius(p1, p2, p3), radius)
+	return g.floatGeometry.inside(p1, p2, p3, radius)
+}
+
+// angle also records the gap to PI, where the angle of angleCondition
+// jumps to -PI and meets the condition: a straight angle meets it or not
+// depending on the sign of a zero.
+func (g marginGeometry) angle(a [2]float64, b [2]float64, c [2]float64, epsilon float64) (bool, bool) {
+	if a != b && b != c {
+		angle := signedAngle(a, b, c)
+		g.record(angle, math.Pi - epsilon)
+		g.record(math.Abs(angle), math.Pi)
+	}
+	return g.floatGeometry.angle(a, b, c, epsilon)
+}
+
+func (g marginGeometry) larger(p1 [2]float64, p2 [2]float64, p3 [2]float64, area float64) bool {
+	g.record(triangleArea(p1, p2, p3), area)
+	return g.floatGeometry.larger(p1, p2, p3, area)
+}
+
+func (g marginGeometry) smaller(p1 [2]float64, p2 [2]float64, p3 [2]float64, area float64) bool {
+	g.record(triangleArea(p1, p2, p3), area)
+	return g.floatGeometry.smaller(p1, p2, p3, area)
+}
+
+func (g marginGeometry) coincident(p [2]float64, q [2]float64) bool {
+	g.record(computeDistancePointToPoint(p, q), 0)
+	return g.floatGeometry.coincident(p, q)
+}
+
+func (g marginGeometry) farFromLine(p [2]float64, p1 [2]float64, p2 [2]float64, dist float64) bool {
+	g.record(lineDistance(p, p1, p2), dist)
+	return g.floatGeometry.farFromLine(p, p1, p2, dist)
+}
+
+func (g marginGeometry) decreasing(p [2]float64, q [2]float64) bool {
+	g.record(q[0], p[0])
+	return g.floatGeometry.decreasing(p, q)
+}
+
+// pointSlice gives access to the points of an input like a stream.
+type pointSlice [][2]float64
+
+func (s pointSlice) at(i int) [2]float64 {
+	return s[i]
+}
+
+// evaluateSets evaluates the LIC lic with the geometry g by testing each
+// of its sets of points, like a stream does for the last set.
+func (d Decide) evaluateSets(lic int, g geometry) (bool, error) {
+	if err := ruleConstraints[lic](d.input); err != nil {
+		return false, err
+	}
+	state := d.scanSets(lic, g, false)
+	if d.ctx != nil && d.ctx.Err() != nil {
+		return false, d.ctx.Err()
+	}
+	return state.value(lic) && d.input.NumPoints >= licMinPoints[lic], nil
+}
+
+// scanSets marks the sets of points of the LIC lic that satisfy each of
+// its conditions with the geometry g. Unless all is set, it stops at the
+// first set that decides the value of the LIC, like Rule0 to Rule14, so
+// that the last set examined is the one that satisfied it.
+func (d Decide) scanSets(lic int, g geometry, all bool) licState {
+	params := d.input.Parameters
+	span := licSpan(lic, params)
+	var state licState
+	for start := 0; span >= 2 && start + span <= d.input.NumPoints; start++ {
+		if d.examine(start) {
+			break
+		}
+		a, b := licSets[lic](pointSlice(d.input.Points), params, g, start)
+		if a {
+			state.mark(0, start)
+		}
+		if b {
+			state.mark(1, start)
+		}
+		if !all && state.decided(lic) {
+			break
+		}
+	}
+	return state
+}
+
+// Margins returns for each LIC the smallest gap, relative to their
+// magnitude, between a quantity it compares on a set of points of input
+// and its threshold in the float evaluation. A disagreement between
+// evaluations on a LIC with a small margin is a matter of precision,
+// while a large margin shows a difference in reading the specification.
+// The LICs that compare no quantity, such as LIC 4, have an infinite
+// margin.
+func Margins(input INPUT) ([NB_LIC]float64, error) {
+	var margins [NB_LIC]float64
+	var e Engine
+	if err := e.checkInput(input); err != nil {
+		return margins, err
+	}
+	d := Decide{input: input}
+	for lic := range margins {
+		margins[lic] = math.Inf(1)
+		if err := ruleConstraints[lic](input); err != nil {
+			return margins, err
+		}
+		d.scanSets(lic, marginGeometry{margin: &margins[lic]}, true)
+	}
+	return margins, nil
+}
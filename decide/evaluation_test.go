@@ -0,0 +1,178 @@
+package decide
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+// gridInput returns an input whose points and parameters are small
+// integers: coincident and collinear points, right angles and quantities
+// equal to their thresholds are frequent.
+func gridInput(seed int64) INPUT {
+	config := DefaultGeneratorConfig()
+	config.NumPoints = Range{5, 20}
+	config.Coordinates = Range{-3, 3}
+	for _, name := range []string{"LENGTH1", "LENGTH2", "RADIUS1", "RADIUS2", "DIST", "AREA1", "AREA2"} {
+		config.Parameters[name] = Range{0, 4}
+	}
+	input, err := GenerateWith(seed, config)
+	if err != nil {
+		panic(err)
+	}
+	for i, p := range input.Points {
+		input.Points[i] = [2]float64{math.Round(p[0]), math.Round(p[1])}
+	}
+	p := &input.Parameters
+	for _, v := range []*float64{&p.LENGTH1, &p.LENGTH2, &p.RADIUS1, &p.RADIUS2, &p.DIST, &p.AREA1, &p.AREA2} {
+		*v = math.Round(*v)
+	}
+	return input
+}
+
+// TestEvaluateSets checks that the float geometry evaluated set by set
+// gives the values of Rule0 to Rule14.
+func TestEvaluateSets(t *testing.T) {
+	for seed := int64(0); seed < 300; seed++ {
+		for _, input := range []INPUT{Generate(seed), gridInput(seed)} {
+			d := Decide{input: input}
+			for lic := 0; lic < NB_LIC; lic++ {
+				d.scan = &scan{exit: -1}
+				expected, errRule := rules[lic](d)
+				ruleScan := *d.scan
+				d.scan = &scan{exit: -1}
+				got, errSets := d.evaluateSets(lic, floatGeometry{})
+				if expected != got || (errRule == nil) != (errSets == nil) {
+					t.Error("The sets and Rule", lic, "disagree for seed", seed, expected, got, errRule, errSets)
+					return
+				}
+				// The trace reports the last set examined as the one
+				// that satisfied the LIC.
+				if expected && ruleScan.last != d.scan.last {
+					t.Error("The sets and Rule", lic, "stop at different sets for seed", seed, ruleScan.last, d.scan.last)
+					return
+				}
+			}
+		}
+	}
+}
+
+// TestEvaluationsAgree checks that the exact and tolerant evaluations
+// agree with the float evaluation on the LICs whose quantities are far
+// from their thresholds.
+func TestEvaluationsAgree(t *testing.T) {
+	for seed := int64(0); seed < 300; seed++ {
+		for _, input := range []INPUT{Generate(seed), gridInput(seed)} {
+			margins, err := Margins(input)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			expected := Decide{}
+			if err := expected.Decide(input); err != nil {
+				t.Error(err)
+				return
+			}
+			for _, evaluation := range []Evaluation{EvaluationTolerant, EvaluationExact} {
+				e := Engine{Evaluation: evaluation}
+				d, err := e.Decide(context.Background(), input)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				for lic := 0; lic < NB_LIC; lic++ {
+					if margins[lic] > 1e-6 && d.CMV[lic] != expected.CMV[lic] {
+						t.Error("The", evaluation, "evaluation disagrees on LIC", lic, "far from its threshold, seed", seed, margins[lic])
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+func evaluateWith(t *testing.T, evaluation Evaluation, lic int, points [][2]float64, params Parameters) bool {
+	t.Helper()
+	g, err := evaluation.geometry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := Decide{}
+	d.input.NumPoints = len(points)
+	d.input.Points = points
+	d.input.Parameters = params
+	v, err := d.evaluateSets(lic, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestEvaluations(t *testing.T) {
+	// An undefined angle stops the search of LIC 2, except in the strict
+	// evaluation.
+	points := [][2]float64{{0, 0}, {0, 0}, {1, 0}, {1, 1}, {2, 1}}
+	if evaluateWith(t, EvaluationFloat, 2, points, Parameters{EPSILON: 0.1}) || !evaluateWith(t, EvaluationStrict, 2, points, Parameters{EPSILON: 0.1}) {
+		t.Error("Expected only the strict evaluation to go past an undefined angle")
+		return
+	}
+
+	// A nearly straight turn in the negative direction meets LIC 2 in the
+	// float evaluation only.
+	points = [][2]float64{{0, 0}, {1, 0}, {2, 0.001}}
+	if !evaluateWith(t, EvaluationFloat, 2, points, Parameters{EPSILON: 0.1}) || evaluateWith(t, EvaluationStrict, 2, points, Parameters{EPSILON: 0.1}) {
+		t.Error("Expected the strict evaluation to measure unsigned angles")
+		return
+	}
+
+	// The smallest circle that contains the points has a radius of 2, the
+	// circle around their centroid a radius of 8/3.
+	points = [][2]float64{{0, 0}, {4, 0}, {4, 0}}
+	if !evaluateWith(t, EvaluationFloat, 1, points, Parameters{RADIUS1: 2.5}) || evaluateWith(t, EvaluationStrict, 1, points, Parameters{RADIUS1: 2.5}) {
+		t.Error("Expected the strict evaluation to use the smallest enclosing circle")
+		return
+	}
+
+	// Within the tolerance, a distance is not greater than LENGTH1.
+	points = [][2]float64{{0, 0}, {1, 0}}
+	params := Parameters{LENGTH1: 1 - 1e-12}
+	if !evaluateWith(t, EvaluationFloat, 0, points, params) || !evaluateWith(t, EvaluationExact, 0, points, params) || evaluateWith(t, EvaluationTolerant, 0, points, params) {
+		t.Error("Expected the tolerant evaluation to ignore a difference of 1e-12")
+		return
+	}
+
+	// The float square root of 13 is below the square root of 13: the
+	// distance between the points is LENGTH1 in float, greater in exact
+	// arithmetic.
+	points = [][2]float64{{0, 0}, {2, 3}}
+	params = Parameters{LENGTH1: math.Sqrt(13)}
+	if evaluateWith(t, EvaluationFloat, 0, points, params) || !evaluateWith(t, EvaluationExact, 0, points, params) {
+		t.Error("Expected the exact evaluation not to round the distance")
+		return
+	}
+}
+
+func TestEngineEvaluation(t *testing.T) {
+	input := Generate(3)
+	e := Engine{Evaluation: EvaluationStrict}
+	d, err := e.Decide(context.Background(), input)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := d.SetProvenance("input.json", time.Unix(0, 0)); err != nil {
+		t.Error(err)
+		return
+	}
+	if d.Provenance.Evaluation != EvaluationStrict {
+		t.Error("Expected the provenance to record the evaluation, got", d.Provenance.Evaluation)
+		return
+	}
+
+	e.Evaluation = "approximate"
+	if _, err := e.Decide(context.Background(), input); err == nil {
+		t.Error("Expected an unknown evaluation to be rejected")
+		return
+	}
+}
@@ -0,0 +1,63 @@
+package decide
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Reason is one link of the causal chain of a NO decision: the FUV entry
+// of a LIC is false because one of the PUM entries of its row is false.
+type Reason struct {
+	LIC   int     `json:"LIC"`
+	Other int     `json:"OTHER"`
+	LCM   Command `json:"LCM"`
+	CMV   [2]bool `json:"CMV"`
+}
+
+func (r Reason) String() string {
+	return fmt.Sprintf("FUV[%d] is false: PUV[%d] is set and PUM[%d][%d] is false (LCM %s, CMV[%d]=%t, CMV[%d]=%t)",
+		r.LIC, r.LIC, r.LIC, r.Other, r.LCM, r.LIC, r.CMV[0], r.Other, r.CMV[1])
+}
+
+// Explanation is the causal chain that leads from the CMV to LAUNCH.
+type Explanation struct {
+	Launch  string   `json:"LAUNCH"`
+	Reasons []Reason `json:"REASONS"`
+}
+
+func (e Explanation) String() string {
+	if len(e.Reasons) == 0 {
+		return fmt.Sprintf("LAUNCH %s: every FUV entry is true", e.Launch)
+	}
+	lines := make([]string, 0, len(e.Reasons) + 1)
+	lines = append(lines, fmt.Sprintf("LAUNCH %s:", e.Launch))
+	for _, r := range e.Reasons {
+		lines = append(lines, "  " + r.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Explain returns the causal chain of the last decision: every false
+// PUM entry of every false FUV entry, with the LCM connector and the
+// CMV values that produced it.
+func (d Decide) Explain() Explanation {
+	explanation := Explanation{Launch: d.Launch}
+	for i := 0; i < NB_LIC; i++ {
+		if d.FUV[i] {
+			continue
+		}
+		for j := 0; j < NB_LIC; j++ {
+			if i == j || d.PUM[i][j] {
+				continue
+			}
+			explanation.Reasons = append(explanation.Reasons, Reason{
+				LIC:   i,
+				Other: j,
+				LCM:   d.input.LCM[strconv.Itoa(i)][j],
+				CMV:   [2]bool{d.CMV[i], d.CMV[j]},
+			})
+		}
+	}
+	return explanation
+}
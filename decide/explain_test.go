@@ -0,0 +1,44 @@
+package decide
+
+import "testing"
+
+func TestExplain(t *testing.T) {
+	input := Generate(3)
+	for i := range input.PUV {
+		input.PUV[i] = false
+	}
+	decide := Decide{}
+	if err := decide.Decide(input); err != nil {
+		t.Error(err)
+		return
+	}
+	explanation := decide.Explain()
+	if explanation.Launch != "YES" || len(explanation.Reasons) != 0 {
+		t.Error("Expected YES without reasons", explanation)
+		return
+	}
+
+	input.PUV[0] = true
+	row := input.LCM["0"]
+	for j := range row {
+		row[j] = NOTUSED
+	}
+	row[4] = ANDD
+	input.LCM["0"] = row
+	input.Parameters.QUADS = 3
+	input.Parameters.Q_PTS = 2
+	if err := decide.Decide(input); err != nil {
+		t.Error(err)
+		return
+	}
+	explanation = decide.Explain()
+	if explanation.Launch != "NO" || len(explanation.Reasons) != 1 {
+		t.Error("Expected NO with one reason", explanation)
+		return
+	}
+	reason := explanation.Reasons[0]
+	if reason.LIC != 0 || reason.Other != 4 || reason.LCM != ANDD || reason.CMV[1] {
+		t.Error("Unexpected reason", reason)
+		return
+	}
+}
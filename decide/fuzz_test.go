@@ -0,0 +1,129 @@
+package decide
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// FuzzDecide decodes JSON inputs and decides them: it must not panic, and
+// a decision without error must be well formed. Run it with
+// go test ./decide -fuzz FuzzDecide.
+func FuzzDecide(f *testing.F) {
+	paths, err := filepath.Glob("../input/input*.json")
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(content)
+	}
+	generated, err := json.Marshal(Generate(1))
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(generated)
+
+	f.Fuzz(func(t *testing.T, content []byte) {
+		var input INPUT
+		if err := json.Unmarshal(content, &input); err != nil {
+			return
+		}
+		// A decision reused for another input must not keep any of its
+		// previous result.
+		d := Decide{}
+		if err := d.Decide(Generate(0)); err != nil {
+			t.Fatal(err)
+		}
+		err := d.Decide(input)
+		if Validate(input) == nil && err != nil {
+			t.Fatal("Expected a valid input to be decided, got", err)
+		}
+		if err == nil {
+			if err := wellFormed(input, d); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := json.Marshal(d); err != nil {
+				t.Fatal("Expected the result to encode", err)
+			}
+		}
+
+		// The other engines must neither panic nor disagree on the
+		// validity of the input, and the concurrent and lazy ones on
+		// the decision.
+		engines := []Engine{{Concurrency: 4}, {Lazy: true}, {Lazy: true, Concurrency: 4}}
+		for _, evaluation := range Evaluations {
+			engines = append(engines, Engine{Evaluation: evaluation})
+		}
+		for _, e := range engines {
+			other, otherErr := e.Decide(context.Background(), input)
+			var panicked PanicError
+			if errors.As(otherErr, &panicked) {
+				t.Fatal("Expected no panic of engine", e, "got", otherErr)
+			}
+			if (err == nil) != (otherErr == nil) {
+				t.Fatal("Expected engine", e, "to agree on the validity of the input,", err, "got", otherErr)
+			}
+			if otherErr != nil {
+				continue
+			}
+			if err := wellFormed(input, other); err != nil {
+				t.Fatal(err)
+			}
+			if e.evaluation() == EvaluationFloat && (other.Launch != d.Launch || other.FUV != d.FUV) {
+				t.Fatal("Expected engine", e, "to decide like Decide, got", Compare(d, other))
+			}
+			if e.Concurrency > 1 && !e.Lazy && other.CMV != d.CMV {
+				t.Fatal("Expected the concurrent CMV to be the sequential one, got", Compare(d, other))
+			}
+		}
+	})
+}
+
+// wellFormed checks that the PUM, FUV and LAUNCH of d follow from its CMV
+// and from the LCM and PUV of input. A missing or unknown LCM entry is
+// never satisfied.
+func wellFormed(input INPUT, d Decide) error {
+	for i := 0; i < NB_LIC; i++ {
+		row := input.LCM[strconv.Itoa(i)]
+		fuv := true
+		for j := 0; j < NB_LIC; j++ {
+			var pum bool
+			switch row[j] {
+			case ANDD:
+				pum = d.CMV[i] && d.CMV[j]
+			case ORR:
+				pum = d.CMV[i] || d.CMV[j]
+			case NOTUSED:
+				pum = true
+			}
+			if d.PUM[i][j] != pum {
+				return fmt.Errorf("PUM[%d][%d] is %v, expected %v", i, j, d.PUM[i][j], pum)
+			}
+			if i != j && !pum {
+				fuv = false
+			}
+		}
+		if d.FUV[i] != (!input.PUV[i] || fuv) {
+			return fmt.Errorf("FUV[%d] is %v", i, d.FUV[i])
+		}
+	}
+	launch := "YES"
+	for _, v := range d.FUV {
+		if !v {
+			launch = "NO"
+		}
+	}
+	if d.Launch != launch {
+		return fmt.Errorf("LAUNCH is %q, expected %q", d.Launch, launch)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package decide
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+)
+
+// Range is an inclusive interval of values. Integer fields are drawn
+// from the integers of the interval.
+type Range struct {
+	Min float64
+	Max float64
+}
+
+// GeneratorConfig bounds the values drawn by GenerateWith.
+type GeneratorConfig struct {
+	NumPoints   Range
+	// MaxPoints is the maximum NUMPOINTS, DefaultMaxPoints when 0.
+	MaxPoints   int
+	Coordinates Range
+	// Parameters holds the range of each field of Parameters, by name.
+	// The integer fields are further restricted to the values that
+	// respect the constraints of the specification.
+	Parameters map[string]Range
+}
+
+// parameterNames lists the fields of Parameters in the order they are drawn.
+var parameterNames = []string{
+	"LENGTH1", "LENGTH2", "RADIUS1", "RADIUS2", "DIST", "AREA1", "AREA2", "EPSILON",
+	"QUADS", "Q_PTS", "N_PTS", "K_PTS", "G_PTS",
+	"A_PTS", "B_PTS", "C_PTS", "D_PTS", "E_PTS", "F_PTS",
+}
+
+// DefaultGeneratorConfig returns ranges similar to those of the input
+// corpus. The corpus was drawn by another generator: its inputs are not
+// reproduced from their random_seed.
+func DefaultGeneratorConfig() GeneratorConfig {
+	config := GeneratorConfig{
+		NumPoints:   Range{2, 100},
+		Coordinates: Range{-1e6, 1e6},
+		Parameters:  make(map[string]Range, len(parameterNames)),
+	}
+	for _, name := range parameterNames {
+		config.Parameters[name] = Range{1, 100}
+	}
+	for _, name := range []string{"LENGTH1", "LENGTH2", "RADIUS1", "RADIUS2", "DIST", "AREA1", "AREA2"} {
+		config.Parameters[name] = Range{0, 1e6}
+	}
+	config.Parameters["EPSILON"] = Range{0, 3.14}
+	config.Parameters["QUADS"] = Range{1, 3}
+	return config
+}
+
+// Generate returns a random INPUT drawn from the default configuration.
+// The same seed always produces the same input.
+func Generate(seed int64) INPUT {
+	input, err := GenerateWith(seed, DefaultGeneratorConfig())
+	if err != nil {
+		panic(err)
+	}
+	return input
+}
+
+// GenerateWith returns a random INPUT that respects every constraint of
+// the specification, with values drawn from the ranges of config. The
+// same seed and config always produce the same input, whose RandomSeed
+// is set to seed. An error is returned when a range cannot be satisfied.
+func GenerateWith(seed int64, config GeneratorConfig) (INPUT, error) {
+	r := rand.New(rand.NewSource(seed))
+
+	input := INPUT{RandomSeed: &seed}
+	maxPoints := config.MaxPoints
+	if maxPoints == 0 {
+		maxPoints = DefaultMaxPoints
+	}
+	n, err := between(r, "NUMPOINTS", config.NumPoints, 2, maxPoints)
+	if err != nil {
+		return input, err
+	}
+	input.NumPoints = n
+	input.Points = make([][2]float64, n)
+	for i := range input.Points {
+		input.Points[i] = [2]float64{uniform(r, config.Coordinates), uniform(r, config.Coordinates)}
+	}
+
+	// the LCM is symmetric: LCM[i][j] == LCM[j][i]
+	commands := []Command{ANDD, ORR, NOTUSED}
+	var lcm [NB_LIC][NB_LIC]Command
+	for i := 0; i < NB_LIC; i++ {
+		for j := i; j < NB_LIC; j++ {
+			lcm[i][j] = commands[r.Intn(len(commands))]
+			lcm[j][i] = lcm[i][j]
+		}
+	}
+	input.LCM = make(map[string][NB_LIC]Command, NB_LIC)
+	for i := 0; i < NB_LIC; i++ {
+		input.LCM[strconv.Itoa(i)] = lcm[i]
+	}
+	for i := range input.PUV {
+		input.PUV[i] = r.Intn(2) == 1
+	}
+
+	p := &input.Parameters
+	for _, name := range parameterNames {
+		rng, ok := config.Parameters[name]
+		if !ok {
+			return input, fmt.Errorf("missing range for %s", name)
+		}
+		switch name {
+		case "LENGTH1":
+			p.LENGTH1 = uniform(r, rng)
+		case "LENGTH2":
+			p.LENGTH2 = uniform(r, rng)
+		case "RADIUS1":
+			p.RADIUS1 = uniform(r, rng)
+		case "RADIUS2":
+			p.RADIUS2 = uniform(r, rng)
+		case "DIST":
+			p.DIST = uniform(r, rng)
+		case "AREA1":
+			p.AREA1 = uniform(r, rng)
+		case "AREA2":
+			p.AREA2 = uniform(r, rng)
+		case "EPSILON":
+			p.EPSILON = uniform(r, rng)
+		case "QUADS":
+			p.QUADS, err = between(r, name, rng, 1, 3)
+		case "Q_PTS":
+			p.Q_PTS, err = between(r, name, rng, 2, n)
+		case "N_PTS":
+			p.N_PTS, err = betweenFrom(r, name, rng, n >= 3, 3, n)
+		case "K_PTS":
+			p.K_PTS, err = betweenFrom(r, name, rng, n >= 3, 1, n - 2)
+		case "G_PTS":
+			p.G_PTS, err = betweenFrom(r, name, rng, n >= 3, 1, n - 2)
+		case "A_PTS":
+			p.A_PTS, err = betweenFrom(r, name, rng, n >= 5, 1, n - 4)
+		case "B_PTS":
+			p.B_PTS, err = betweenFrom(r, name, rng, n >= 5, 1, n - 3 - p.A_PTS)
+		case "C_PTS":
+			p.C_PTS, err = betweenFrom(r, name, rng, n >= 5, 1, n - 4)
+		case "D_PTS":
+			p.D_PTS, err = betweenFrom(r, name, rng, n >= 5, 1, n - 3 - p.C_PTS)
+		case "E_PTS":
+			p.E_PTS, err = betweenFrom(r, name, rng, n >= 5, 1, n - 4)
+		case "F_PTS":
+			p.F_PTS, err = betweenFrom(r, name, rng, n >= 5, 1, n - 3 - p.E_PTS)
+		}
+		if err != nil {
+			return input, err
+		}
+	}
+	return input, nil
+}
+
+// betweenFrom draws an integer of rng restricted to [lo, hi] when the
+// constraint applies, or of rng only otherwise.
+func betweenFrom(r *rand.Rand, name string, rng Range, constrained bool, lo int, hi int) (int, error) {
+	if !constrained {
+		return between(r, name, rng, math.MinInt32, math.MaxInt32)
+	}
+	return between(r, name, rng, lo, hi)
+}
+
+// between draws an integer of rng restricted to [lo, hi].
+func between(r *rand.Rand, name string, rng Range, lo int, hi int) (int, error) {
+	lo = max(lo, int(math.Ceil(rng.Min)))
+	hi = min(hi, int(math.Floor(rng.Max)))
+	if lo > hi {
+		return 0, fmt.Errorf("no valid value for %s in [%g, %g]", name, rng.Min, rng.Max)
+	}
+	return lo + r.Intn(hi - lo + 1), nil
+}
+
+// uniform draws a float of rng rounded to three decimals, like the
+// values of the input corpus.
+func uniform(r *rand.Rand, rng Range) float64 {
+	v := math.Round((rng.Min + r.Float64() * (rng.Max - rng.Min)) * 1000) / 1000
+	return math.Max(rng.Min, math.Min(rng.Max, v))
+}
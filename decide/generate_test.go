@@ -0,0 +1,95 @@
+package decide
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	for seed := int64(0); seed < 100; seed++ {
+		input := Generate(seed)
+		if err := Validate(input); err != nil {
+			t.Error("Invalid input for seed", seed, err)
+			return
+		}
+		if !reflect.DeepEqual(input, Generate(seed)) {
+			t.Error("Generate is not deterministic for seed", seed)
+			return
+		}
+		for i := 0; i < NB_LIC; i++ {
+			for j := 0; j < NB_LIC; j++ {
+				if input.LCM[strconv.Itoa(i)][j] != input.LCM[strconv.Itoa(j)][i] {
+					t.Error("LCM is not symmetric for seed", seed)
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateWith(t *testing.T) {
+	config := DefaultGeneratorConfig()
+	config.NumPoints = Range{10, 20}
+	config.Coordinates = Range{-1, 1}
+	config.Parameters["Q_PTS"] = Range{3, 4}
+	config.Parameters["LENGTH1"] = Range{5, 6}
+	for seed := int64(0); seed < 100; seed++ {
+		input, err := GenerateWith(seed, config)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if err := Validate(input); err != nil {
+			t.Error("Invalid input for seed", seed, err)
+			return
+		}
+		if input.RandomSeed == nil || *input.RandomSeed != seed {
+			t.Error("Expected random_seed", seed)
+			return
+		}
+		if input.NumPoints < 10 || input.NumPoints > 20 {
+			t.Error("NUMPOINTS out of range", input.NumPoints)
+			return
+		}
+		for _, p := range input.Points {
+			if math.Abs(p[0]) > 1 || math.Abs(p[1]) > 1 {
+				t.Error("Point out of range", p)
+				return
+			}
+		}
+		if input.Parameters.Q_PTS < 3 || input.Parameters.Q_PTS > 4 {
+			t.Error("Q_PTS out of range", input.Parameters.Q_PTS)
+			return
+		}
+		if input.Parameters.LENGTH1 < 5 || input.Parameters.LENGTH1 > 6 {
+			t.Error("LENGTH1 out of range", input.Parameters.LENGTH1)
+			return
+		}
+	}
+
+	config.Parameters["QUADS"] = Range{4, 5}
+	if _, err := GenerateWith(1, config); err == nil {
+		t.Error("Unsatisfiable QUADS expected")
+		return
+	}
+}
+
+func TestRandomSeedRoundTrip(t *testing.T) {
+	content, err := json.Marshal(Generate(1189720512))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var input INPUT
+	if err := json.Unmarshal(content, &input); err != nil {
+		t.Error(err)
+		return
+	}
+	if input.RandomSeed == nil || *input.RandomSeed != 1189720512 {
+		t.Error("Expected random_seed to be preserved")
+		return
+	}
+}
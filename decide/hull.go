@@ -0,0 +1,184 @@
+package decide
+
+import (
+	"sort"
+	"sync"
+)
+
+// hullBlock is the number of points of the blocks of a hull tree, scanned
+// directly rather than through their hull.
+const hullBlock = 16
+
+// hullMinPoints is the smallest N_PTS for which Rule6 queries a hull tree
+// rather than scanning the points of each set.
+const hullMinPoints = 4 * hullBlock
+
+// hullTree answers, for a range of consecutive points, which point is the
+// farthest in a direction. The linear function of the distance of Rule6
+// is extreme, over a set of points, at a vertex of their convex hull:
+// the tree holds the hull of every block of hullBlock points and of every
+// node of a segment tree over the blocks, so that a range is covered by
+// its partial blocks at both ends and O(log NUMPOINTS) hulls, each
+// searched in O(log N_PTS).
+type hullTree struct {
+	points [][2]float64
+	// size is the number of leaves of the segment tree, a power of two
+	// at least the number of blocks. The node k has the children 2k and
+	// 2k+1, the block b is the leaf size+b.
+	size int
+	// upper and lower are the upper and lower hulls of each node, by
+	// increasing x then y, as indices of points.
+	upper [][]int32
+	lower [][]int32
+}
+
+func newHullTree(points [][2]float64) *hullTree {
+	blocks := (len(points) + hullBlock - 1) / hullBlock
+	t := &hullTree{points: points, size: 1}
+	for t.size < blocks {
+		t.size *= 2
+	}
+	t.upper = make([][]int32, 2 * t.size)
+	t.lower = make([][]int32, 2 * t.size)
+	for b := 0; b < blocks; b++ {
+		indices := make([]int32, 0, hullBlock)
+		for i := b * hullBlock; i < min((b + 1) * hullBlock, len(points)); i++ {
+			indices = append(indices, int32(i))
+		}
+		t.upper[t.size + b], t.lower[t.size + b] = t.hull(indices)
+	}
+	for k := t.size - 1; k >= 1; k-- {
+		var indices []int32
+		for _, child := range []int{2 * k, 2 * k + 1} {
+			indices = append(indices, t.upper[child]...)
+			indices = append(indices, t.lower[child]...)
+		}
+		t.upper[k], t.lower[k] = t.hull(indices)
+	}
+	return t
+}
+
+// hull returns the upper and lower hulls of the points of indices, with
+// Andrew's monotone chain.
+func (t *hullTree) hull(indices []int32) ([]int32, []int32) {
+	if len(indices) == 0 {
+		return nil, nil
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		a, b := t.points[indices[i]], t.points[indices[j]]
+		return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+	})
+	chain := func(order func(int) int32) []int32 {
+		var h []int32
+		for i := range indices {
+			p := order(i)
+			for len(h) >= 2 && cross(t.points[h[len(h) - 2]], t.points[h[len(h) - 1]], t.points[p]) <= 0 {
+				h = h[:len(h) - 1]
+			}
+			h = append(h, p)
+		}
+		return h
+	}
+	lower := chain(func(i int) int32 { return indices[i] })
+	upper := chain(func(i int) int32 { return indices[len(indices) - 1 - i] })
+	for i, j := 0, len(upper) - 1; i < j; i, j = i + 1, j - 1 {
+		upper[i], upper[j] = upper[j], upper[i]
+	}
+	return upper, lower
+}
+
+// cross returns the cross product of b - a and c - a, positive when a, b
+// and c turn counterclockwise.
+func cross(a [2]float64, b [2]float64, c [2]float64) float64 {
+	return (b[0] - a[0]) * (c[1] - a[1]) - (b[1] - a[1]) * (c[0] - a[0])
+}
+
+// extreme returns the point of the range [from, to) farthest in the
+// direction dir, which maximises dir[0] x + dir[1] y.
+func (t *hullTree) extreme(from int, to int, dir [2]float64) int {
+	best, value := -1, 0.0
+	consider := func(i int) {
+		if v := dir[0] * t.points[i][0] + dir[1] * t.points[i][1]; best < 0 || v > value {
+			best, value = i, v
+		}
+	}
+	t.cover(from, to, consider, func(k int) {
+		// The edges of the upper hull turn clockwise, so that the
+		// product of dir with them decreases along the hull when dir
+		// points up, and likewise the lower hull when dir points down.
+		h := t.upper[k]
+		if dir[1] < 0 {
+			h = t.lower[k]
+		}
+		lo, hi := 0, len(h) - 1
+		for lo < hi {
+			mid := (lo + hi) / 2
+			a, b := t.points[h[mid]], t.points[h[mid + 1]]
+			if dir[0] * (b[0] - a[0]) + dir[1] * (b[1] - a[1]) < 0 {
+				hi = mid
+			} else {
+				lo = mid + 1
+			}
+		}
+		consider(int(h[lo]))
+	})
+	return best
+}
+
+// vertices calls f with the points of the range [from, to) that may be the
+// farthest from a point: the vertices of the hulls that cover it. Unlike
+// extreme it is linear in the size of the range when its points are in
+// convex position.
+func (t *hullTree) vertices(from int, to int, f func(i int)) {
+	t.cover(from, to, f, func(k int) {
+		for _, i := range t.upper[k] {
+			f(int(i))
+		}
+		for _, i := range t.lower[k] {
+			f(int(i))
+		}
+	})
+}
+
+// cover calls point with the points of the partial blocks of the range
+// [from, to) and node with the nodes that cover its whole blocks.
+func (t *hullTree) cover(from int, to int, point func(int), node func(int)) {
+	first, last := (from + hullBlock - 1) / hullBlock, to / hullBlock
+	if first >= last {
+		for i := from; i < to; i++ {
+			point(i)
+		}
+		return
+	}
+	for i := from; i < first * hullBlock; i++ {
+		point(i)
+	}
+	for i := last * hullBlock; i < to; i++ {
+		point(i)
+	}
+	for lo, hi := first + t.size, last + t.size; lo < hi; lo, hi = lo / 2, hi / 2 {
+		if lo % 2 == 1 {
+			node(lo)
+			lo++
+		}
+		if hi % 2 == 1 {
+			hi--
+			node(hi)
+		}
+	}
+}
+
+// lazyHulls builds the hull tree of the points the first time Rule6 needs
+// it. It is safe for concurrent use.
+type lazyHulls struct {
+	once   sync.Once
+	points [][2]float64
+	tree   *hullTree
+}
+
+func (l *lazyHulls) get() *hullTree {
+	l.once.Do(func() {
+		l.tree = newHullTree(l.points)
+	})
+	return l.tree
+}
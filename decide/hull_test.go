@@ -0,0 +1,109 @@
+package decide
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestRule6Hulls checks that Rule6 decides like the scan of every point
+// when it searches the hulls, on random points, on a grid where points
+// coincide and align, on a circle where every point is a vertex, and on a
+// closed track whose sets start and end at the same point.
+func TestRule6Hulls(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tracks := map[string]func(i int) [2]float64{
+		"random": func(i int) [2]float64 { return [2]float64{r.Float64() * 100, r.Float64() * 100} },
+		"grid":   func(i int) [2]float64 { return [2]float64{float64(r.Intn(7)), float64(r.Intn(7))} },
+		"circle": func(i int) [2]float64 {
+			a := float64(i) / 500
+			return [2]float64{50 * math.Cos(a), 50 * math.Sin(a)}
+		},
+		"closed": func(i int) [2]float64 {
+			a := 2 * math.Pi * float64(i % 99) / 99
+			return [2]float64{math.Round(10 * math.Cos(a)), math.Round(10 * math.Sin(a))}
+		},
+	}
+	for name, track := range tracks {
+		for trial := 0; trial < 40; trial++ {
+			input := Generate(int64(trial))
+			input.NumPoints = 200 + r.Intn(300)
+			input.Points = make([][2]float64, input.NumPoints)
+			for i := range input.Points {
+				input.Points[i] = track(i)
+			}
+			input.Parameters.N_PTS = hullMinPoints + r.Intn(input.NumPoints - hullMinPoints + 1)
+			if name == "closed" {
+				input.Parameters.N_PTS = 100
+			}
+			// The greatest distance of a set, met by one set at most
+			// just below it and by none at it.
+			input.Parameters.DIST = rule6Greatest(input)
+			if trial % 2 == 0 {
+				input.Parameters.DIST = math.Nextafter(input.Parameters.DIST, 0)
+			}
+
+			scanned := Decide{input: input, scan: &scan{exit: -1}}
+			expected, err := scanned.Rule6()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			hulls := Decide{input: input, hulls: &lazyHulls{points: input.Points}, scan: &scan{exit: -1}}
+			got, err := hulls.Rule6()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if got != expected || hulls.scan.last != scanned.scan.last {
+				t.Error("Expected the hulls to decide Rule6 like the scan on the", name, "track, trial", trial, expected, got, scanned.scan.last, hulls.scan.last)
+				return
+			}
+		}
+	}
+}
+
+func TestHullExtreme(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	points := make([][2]float64, 1000)
+	for i := range points {
+		points[i] = [2]float64{float64(r.Intn(20)), float64(r.Intn(20))}
+	}
+	tree := newHullTree(points)
+	for trial := 0; trial < 2000; trial++ {
+		from := r.Intn(len(points))
+		to := from + 1 + r.Intn(len(points) - from)
+		dir := [2]float64{float64(r.Intn(7) - 3), float64(r.Intn(7) - 3)}
+		best := math.Inf(-1)
+		for i := from; i < to; i++ {
+			best = math.Max(best, dir[0] * points[i][0] + dir[1] * points[i][1])
+		}
+		j := tree.extreme(from, to, dir)
+		if j < from || j >= to || dir[0] * points[j][0] + dir[1] * points[j][1] != best {
+			t.Error("Expected the point of", from, to, "farthest in", dir, "to reach", best, "got", j)
+			return
+		}
+	}
+}
+
+// rule6Greatest returns the greatest distance Rule6 compares with DIST on
+// the sets of input.
+func rule6Greatest(input INPUT) float64 {
+	greatest := 0.0
+	n := input.Parameters.N_PTS
+	for i := 0; i + n <= input.NumPoints; i++ {
+		p1, p2 := input.Points[i], input.Points[i + n - 1]
+		if computeDistancePointToPoint(p1, p2) == 0 {
+			for j := i; j < i + n; j++ {
+				greatest = math.Max(greatest, computeDistancePointToPoint(input.Points[j], p1))
+			}
+			continue
+		}
+		line := computeEquationLine(p1, p2)
+		norm := computeNormLine(line)
+		for j := i + 1; j < i + n - 1; j++ {
+			greatest = math.Max(greatest, computeDistancePointToLineNorm(input.Points[j], line, norm))
+		}
+	}
+	return greatest
+}
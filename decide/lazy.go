@@ -0,0 +1,28 @@
+package decide
+
+import "strconv"
+
+// Relevant returns the LICs that can change the launch decision of
+// input whatever their value. LAUNCH depends on the FUV entries, and
+// FUV[i] only depends on the row i of the PUM when PUV[i] is set. In
+// that row, PUM[i][j] depends on CMV[i] and CMV[j] unless LCM[i][j] is
+// NOTUSED.
+func Relevant(input INPUT) [NB_LIC]bool {
+	var relevant [NB_LIC]bool
+	for i := 0; i < NB_LIC; i++ {
+		if !input.PUV[i] {
+			continue
+		}
+		row := input.LCM[strconv.Itoa(i)]
+		for j := 0; j < NB_LIC; j++ {
+			if i == j {
+				continue
+			}
+			if row[j] == ANDD || row[j] == ORR {
+				relevant[i] = true
+				relevant[j] = true
+			}
+		}
+	}
+	return relevant
+}
@@ -0,0 +1,139 @@
+package decide
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestRelevant(t *testing.T) {
+	input := INPUT{LCM: map[string][NB_LIC]Command{}}
+	for i := 0; i < NB_LIC; i++ {
+		var row [NB_LIC]Command
+		for j := range row {
+			row[j] = NOTUSED
+		}
+		input.LCM[strconv.Itoa(i)] = row
+	}
+	row := input.LCM["3"]
+	row[3] = ANDD
+	row[7] = ORR
+	input.LCM["3"] = row
+	row = input.LCM["5"]
+	row[9] = ANDD
+	input.LCM["5"] = row
+	input.PUV[3] = true
+
+	relevant := Relevant(input)
+	for i, r := range relevant {
+		if r != (i == 3 || i == 7) {
+			t.Error("Unexpected relevance of LIC", i, relevant)
+			return
+		}
+	}
+}
+
+// sparse returns a generated input whose PUV and LCM make few LICs
+// relevant.
+func sparse(seed int64) INPUT {
+	input := Generate(seed)
+	r := rand.New(rand.NewSource(seed))
+	for i := range input.PUV {
+		input.PUV[i] = r.Intn(4) == 0
+	}
+	for i := 0; i < NB_LIC; i++ {
+		row := input.LCM[strconv.Itoa(i)]
+		for j := range row {
+			if r.Intn(3) != 0 {
+				row[j] = NOTUSED
+			}
+		}
+		input.LCM[strconv.Itoa(i)] = row
+	}
+	return input
+}
+
+func TestLazy(t *testing.T) {
+	full := Engine{}
+	skipped := 0
+	for _, lazy := range []Engine{{Lazy: true}, {Lazy: true, Concurrency: 4}} {
+		for seed := int64(0); seed < 200; seed++ {
+			input := sparse(seed)
+			expected, err := full.Decide(context.Background(), input)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			d, err := lazy.Decide(context.Background(), input)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if d.Launch != expected.Launch || d.FUV != expected.FUV {
+				t.Error("The lazy decision differs for seed", seed, Compare(expected, d))
+				return
+			}
+			relevant := Relevant(input)
+			next := 0
+			for i := 0; i < NB_LIC; i++ {
+				if relevant[i] {
+					if d.CMV[i] != expected.CMV[i] {
+						t.Error("The CMV of the relevant LIC", i, "differs for seed", seed)
+						return
+					}
+					continue
+				}
+				if next >= len(d.NotEvaluated) || d.NotEvaluated[next] != i {
+					t.Error("Expected LIC", i, "to be marked not evaluated for seed", seed, d.NotEvaluated)
+					return
+				}
+				next++
+			}
+			skipped += len(d.NotEvaluated)
+		}
+	}
+	if skipped == 0 {
+		t.Error("Expected some LICs to be skipped")
+		return
+	}
+}
+
+func TestLazyErrors(t *testing.T) {
+	input := sparse(1)
+	relevant := Relevant(input)
+	if relevant[0] {
+		t.Skip("LIC 0 is relevant")
+	}
+	input.Parameters.LENGTH1 = -1
+	lazy := Engine{Lazy: true}
+	if _, err := lazy.Decide(context.Background(), input); err == nil || err.Error() != "Invalid length1" {
+		t.Error("Expected the parameters of a skipped LIC to be checked, got", err)
+		return
+	}
+}
+
+func TestDistancesOnFirstUse(t *testing.T) {
+	input := Generate(1)
+	input.PUV = [NB_LIC]bool{}
+	for _, e := range []Engine{{Lazy: true}, {Evaluation: EvaluationStrict}} {
+		d := Decide{}
+		if err := e.decide(context.Background(), &d, input); err != nil {
+			t.Error(err)
+			return
+		}
+		if d.kDistances == nil || d.kDistances.values != nil {
+			t.Error("Expected the distances of K_PTS not to be computed by", e)
+			return
+		}
+	}
+	d := Decide{}
+	if err := d.Decide(input); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(d.kDistances.values) != input.NumPoints - input.Parameters.K_PTS - 1 {
+		t.Error("Expected Rule7 to compute the distances of K_PTS")
+		return
+	}
+}
@@ -0,0 +1,354 @@
+package decide
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+var propertySeed = flag.Int64("property.seed", 1, "the seed of the property-based tests")
+
+const propertyRuns = 500
+
+// sample is a test case of a property: the points of an input, a
+// threshold, a gap in points, and a transformation.
+type sample struct {
+	Points    [][2]float64
+	Threshold float64
+	Gap       int
+	Angle     float64
+	Shift     [2]float64
+	Scale     float64
+}
+
+func (s sample) String() string {
+	return fmt.Sprintf("points %v threshold %v gap %d angle %v shift %v scale %v", s.Points, s.Threshold, s.Gap, s.Angle, s.Shift, s.Scale)
+}
+
+// randomSample draws a sample of 3 to 12 points.
+func randomSample(r *rand.Rand) sample {
+	s := sample{
+		Points:    make([][2]float64, 3 + r.Intn(10)),
+		Threshold: r.Float64() * 100,
+		Gap:       1 + r.Intn(5),
+		Angle:     r.Float64() * 2 * math.Pi,
+		Shift:     [2]float64{r.Float64() * 2000 - 1000, r.Float64() * 2000 - 1000},
+		Scale:     0.1 + r.Float64() * 10,
+	}
+	for i := range s.Points {
+		s.Points[i] = [2]float64{r.Float64() * 200 - 100, r.Float64() * 200 - 100}
+	}
+	return s
+}
+
+// shrinks returns the samples simpler than s: with a point removed, or
+// with a value rounded towards an integer or zero.
+func shrinks(s sample) []sample {
+	var candidates []sample
+	with := func(f func(c *sample)) {
+		c := s
+		c.Points = append([][2]float64(nil), s.Points...)
+		f(&c)
+		candidates = append(candidates, c)
+	}
+	if len(s.Points) > 3 {
+		for i := range s.Points {
+			i := i
+			with(func(c *sample) {
+				c.Points = append(c.Points[:i:i], s.Points[i + 1:]...)
+			})
+		}
+	}
+	simpler := func(v float64) []float64 {
+		var values []float64
+		for _, w := range []float64{0, math.Trunc(v), math.Round(v * 10) / 10} {
+			if w != v {
+				values = append(values, w)
+			}
+		}
+		return values
+	}
+	for i := range s.Points {
+		for k := 0; k < 2; k++ {
+			for _, v := range simpler(s.Points[i][k]) {
+				i, k, v := i, k, v
+				with(func(c *sample) { c.Points[i][k] = v })
+			}
+		}
+	}
+	for _, v := range simpler(s.Threshold) {
+		v := v
+		with(func(c *sample) { c.Threshold = v })
+	}
+	if s.Gap > 1 {
+		with(func(c *sample) { c.Gap = 1 })
+	}
+	for _, v := range simpler(s.Shift[0]) {
+		v := v
+		with(func(c *sample) { c.Shift[0] = v })
+	}
+	for _, v := range simpler(s.Shift[1]) {
+		v := v
+		with(func(c *sample) { c.Shift[1] = v })
+	}
+	return candidates
+}
+
+// shrink returns the simplest sample found from s that still fails.
+func shrink(s sample, property func(sample) error) (sample, error) {
+	err := property(s)
+	for changed := true; changed; {
+		changed = false
+		for _, c := range shrinks(s) {
+			if cerr := property(c); cerr != nil {
+				s, err, changed = c, cerr, true
+				break
+			}
+		}
+	}
+	return s, err
+}
+
+// checkProperty runs property on random samples and reports the first
+// failure, shrunk to a minimal counterexample.
+func checkProperty(t *testing.T, property func(sample) error) {
+	r := rand.New(rand.NewSource(*propertySeed))
+	for run := 0; run < propertyRuns; run++ {
+		s := randomSample(r)
+		if property(s) == nil {
+			continue
+		}
+		s, err := shrink(s, property)
+		t.Errorf("Property failed after %d runs with -property.seed %d: %s\n%s", run, *propertySeed, err, s)
+		return
+	}
+}
+
+// evaluate evaluates the LIC lic on points with params.
+func evaluate(lic int, points [][2]float64, params Parameters) bool {
+	d := Decide{}
+	d.input.NumPoints = len(points)
+	d.input.Points = points
+	d.input.Parameters = params
+	v, err := d.Rule(lic)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// robust checks that a rule of the form "some quantity > threshold"
+// gives on the transformed points a value that the original points give
+// for a threshold within a relative tolerance: floating point errors can
+// only flip the rules close to the threshold.
+func robust(lic int, s sample, transformed [][2]float64, params func(threshold float64) Parameters, factor float64) error {
+	const tolerance = 1e-9
+	got := evaluate(lic, transformed, params(s.Threshold * factor))
+	low := evaluate(lic, s.Points, params(s.Threshold * (1 - tolerance)))
+	high := evaluate(lic, s.Points, params(s.Threshold * (1 + tolerance) + tolerance))
+	// The rule is true for low thresholds: high implies got implies low.
+	if (high && !got) || (got && !low) {
+		return fmt.Errorf("Rule%d is %v after the transformation, %v/%v before", lic, got, high, low)
+	}
+	return nil
+}
+
+func transform(points [][2]float64, f func(p [2]float64) [2]float64) [][2]float64 {
+	result := make([][2]float64, len(points))
+	for i, p := range points {
+		result[i] = f(p)
+	}
+	return result
+}
+
+func (s sample) translated() [][2]float64 {
+	return transform(s.Points, func(p [2]float64) [2]float64 {
+		return [2]float64{p[0] + s.Shift[0], p[1] + s.Shift[1]}
+	})
+}
+
+func (s sample) rotated() [][2]float64 {
+	sin, cos := math.Sincos(s.Angle)
+	return transform(s.Points, func(p [2]float64) [2]float64 {
+		return [2]float64{p[0] * cos - p[1] * sin, p[0] * sin + p[1] * cos}
+	})
+}
+
+func (s sample) scaled() [][2]float64 {
+	return transform(s.Points, func(p [2]float64) [2]float64 {
+		return [2]float64{p[0] * s.Scale, p[1] * s.Scale}
+	})
+}
+
+// invariantParams returns the parameters of the rules 0, 1, 3 and 7 for
+// a threshold and the gap of s.
+func invariantParams(lic int, s sample) func(float64) Parameters {
+	return func(threshold float64) Parameters {
+		var p Parameters
+		switch lic {
+		case 0, 7:
+			p.LENGTH1 = threshold
+			p.K_PTS = 1 + (s.Gap - 1) % (len(s.Points) - 2)
+		case 1:
+			p.RADIUS1 = threshold
+		case 3:
+			p.AREA1 = threshold
+		}
+		return p
+	}
+}
+
+func TestPropertyTranslation(t *testing.T) {
+	for _, lic := range []int{0, 1, 3, 7} {
+		lic := lic
+		t.Run(fmt.Sprintf("Rule%d", lic), func(t *testing.T) {
+			checkProperty(t, func(s sample) error {
+				return robust(lic, s, s.translated(), invariantParams(lic, s), 1)
+			})
+		})
+	}
+}
+
+func TestPropertyRotation(t *testing.T) {
+	for _, lic := range []int{0, 1, 3, 7} {
+		lic := lic
+		t.Run(fmt.Sprintf("Rule%d", lic), func(t *testing.T) {
+			checkProperty(t, func(s sample) error {
+				return robust(lic, s, s.rotated(), invariantParams(lic, s), 1)
+			})
+		})
+	}
+}
+
+// areaParams returns the parameters of the rules 3 and 10 for an area.
+func areaParams() func(float64) Parameters {
+	return func(area float64) Parameters {
+		var p Parameters
+		p.AREA1 = area
+		p.E_PTS = 1
+		p.F_PTS = 1
+		return p
+	}
+}
+
+func TestPropertyScaling(t *testing.T) {
+	for _, lic := range []int{3, 10} {
+		lic := lic
+		t.Run(fmt.Sprintf("Rule%d", lic), func(t *testing.T) {
+			checkProperty(t, func(s sample) error {
+				if lic == 10 && len(s.Points) < 5 {
+					return nil
+				}
+				return robust(lic, s, s.scaled(), areaParams(), s.Scale * s.Scale)
+			})
+		})
+	}
+}
+
+func TestPropertyMirror(t *testing.T) {
+	checkProperty(t, func(s sample) error {
+		mirrored := transform(s.Points, func(p [2]float64) [2]float64 {
+			return [2]float64{-p[0], p[1]}
+		})
+		reversed := make([][2]float64, len(s.Points))
+		for i, p := range s.Points {
+			reversed[len(s.Points) - 1 - i] = p
+		}
+		// An increasing x in the track is a decreasing x both in the
+		// mirrored and in the reversed track.
+		if evaluate(5, mirrored, Parameters{}) != evaluate(5, reversed, Parameters{}) {
+			return fmt.Errorf("Rule5 differs on the mirrored and on the reversed points")
+		}
+
+		sorted := append([][2]float64(nil), s.Points...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i][0] < sorted[j][0] })
+		for i := 1; i < len(sorted); i++ {
+			if sorted[i][0] == sorted[i - 1][0] {
+				return nil
+			}
+		}
+		mirroredSorted := transform(sorted, func(p [2]float64) [2]float64 {
+			return [2]float64{-p[0], p[1]}
+		})
+		if evaluate(5, sorted, Parameters{}) || !evaluate(5, mirroredSorted, Parameters{}) {
+			return fmt.Errorf("Rule5 does not flip on points of increasing x")
+		}
+		return nil
+	})
+}
+
+// shoelace returns the area of the polygon of points.
+func shoelace(points ...[2]float64) float64 {
+	sum := 0.0
+	for i, p := range points {
+		q := points[(i + 1) % len(points)]
+		sum += p[0] * q[1] - q[0] * p[1]
+	}
+	return math.Abs(sum) / 2
+}
+
+// TestPropertyShoelace checks the area rules against the area of the
+// triangles of their windows computed with the shoelace formula.
+func TestPropertyShoelace(t *testing.T) {
+	const tolerance = 1e-9
+	for _, lic := range []int{3, 10, 14} {
+		lic := lic
+		t.Run(fmt.Sprintf("Rule%d", lic), func(t *testing.T) {
+			checkProperty(t, func(s sample) error {
+				n := len(s.Points)
+				gap := 0
+				if lic != 3 {
+					if n < 5 {
+						return nil
+					}
+					gap = 1 + (s.Gap - 1) % ((n - 3) / 2)
+				}
+				// The largest area of the triangles of the windows.
+				largest := 0.0
+				for i := 0; i + 2 * gap + 2 < n; i++ {
+					largest = math.Max(largest, shoelace(s.Points[i], s.Points[i + gap + 1], s.Points[i + 2 * gap + 2]))
+				}
+				params := Parameters{AREA1: s.Threshold, E_PTS: gap, F_PTS: gap}
+				if lic == 14 {
+					// The second part of Rule14 always holds.
+					params.AREA2 = math.Inf(1)
+				}
+				got := evaluate(lic, s.Points, params)
+				if math.Abs(largest - s.Threshold) <= tolerance * math.Max(1, s.Threshold) {
+					return nil
+				}
+				if got != (largest > s.Threshold) {
+					return fmt.Errorf("Rule%d is %v but the largest area is %v", lic, got, largest)
+				}
+				return nil
+			})
+		})
+	}
+}
+
+// TestShrink checks that a failing property shrinks to a minimal sample.
+func TestShrink(t *testing.T) {
+	property := func(s sample) error {
+		for _, p := range s.Points {
+			if p[0] > 10 {
+				return fmt.Errorf("x > 10")
+			}
+		}
+		return nil
+	}
+	s := sample{Points: [][2]float64{{1.5, 2.25}, {42.42, -3.5}, {0.5, 7}, {-4, 1}, {3, 3}}, Threshold: 3.3}
+	s, err := shrink(s, property)
+	if err == nil || len(s.Points) != 3 || s.Threshold != 0 {
+		t.Error("Expected a minimal failing sample, got", s)
+		return
+	}
+	for _, p := range s.Points {
+		if p[0] != 0 && p[0] != 42 || p[1] != 0 {
+			t.Error("Expected the coordinates to shrink, got", s)
+			return
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package decide
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"runtime"
+	"time"
+)
+
+// Version identifies the build of the engine. It is overridden at link
+// time with -ldflags "-X github.com/tdurieux/go-decide/decide.Version=...".
+var Version = "1.0.0"
+
+// Mode is the way the LICs are evaluated.
+type Mode string
+
+const (
+	// ModeSequential evaluates the LICs one after the other.
+	ModeSequential Mode = "sequential"
+	// ModeConcurrent evaluates the LICs concurrently.
+	ModeConcurrent Mode = "concurrent"
+)
+
+// Provenance traces a result back to the input and the engine that
+// produced it.
+type Provenance struct {
+	Input         string    `json:"INPUT"`
+	InputSHA256   string    `json:"INPUT_SHA256"`
+	RandomSeed    *int64    `json:"RANDOM_SEED,omitempty"`
+	EngineVersion string    `json:"ENGINE_VERSION"`
+	Mode          Mode      `json:"MODE"`
+	// Evaluation is the evaluation of the LICs, omitted for
+	// EvaluationFloat.
+	Evaluation    Evaluation `json:"EVALUATION,omitempty"`
+	// MaxPoints is the maximum NUMPOINTS of the engine, omitted for
+	// DefaultMaxPoints.
+	MaxPoints     int       `json:"MAX_POINTS,omitempty"`
+	// Bounds is the bounding box of the points of the engine, omitted
+	// when they were not bounded.
+	Bounds        *Bounds   `json:"BOUNDS,omitempty"`
+	// Lazy reports that the LICs that cannot change the launch decision
+	// were not evaluated, see NOT_EVALUATED.
+	Lazy          bool      `json:"LAZY,omitempty"`
+	Timestamp     time.Time `json:"TIMESTAMP"`
+}
+
+// Engine returns an engine that evaluates inputs like the engine that
+// produced the result, so that the decision can be replayed.
+func (p *Provenance) Engine() *Engine {
+	e := &Engine{Evaluation: p.Evaluation, MaxPoints: p.MaxPoints, Bounds: p.Bounds, Lazy: p.Lazy}
+	if p.Mode == ModeConcurrent {
+		e.Concurrency = max(runtime.GOMAXPROCS(0), 2)
+	}
+	return e
+}
+
+// Canonical returns the canonical JSON encoding of the input: the fields
+// in declaration order, the LCM rows sorted by key and no whitespace.
+// Two documents that decode to the same INPUT have the same encoding.
+func Canonical(input INPUT) ([]byte, error) {
+	return json.Marshal(input)
+}
+
+// Hash returns the hex encoded SHA-256 of the canonical input.
+func Hash(input INPUT) (string, error) {
+	content, err := Canonical(input)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// SetProvenance records in the result where its input comes from, the
+// hash of the input and the engine that evaluated it at timestamp.
+// It must be called after Decide.
+func (d *Decide) SetProvenance(inputPath string, timestamp time.Time) error {
+	hash, err := Hash(d.input)
+	if err != nil {
+		return err
+	}
+	d.Provenance = &Provenance{
+		Input:         inputPath,
+		InputSHA256:   hash,
+		RandomSeed:    d.input.RandomSeed,
+		EngineVersion: Version,
+		Mode:          d.mode,
+		Lazy:          d.lazy,
+		Timestamp:     timestamp.UTC(),
+	}
+	if d.evaluation != EvaluationFloat {
+		d.Provenance.Evaluation = d.evaluation
+	}
+	if d.maxPoints != DefaultMaxPoints {
+		d.Provenance.MaxPoints = d.maxPoints
+	}
+	if d.bounds != nil {
+		bounds := *d.bounds
+		d.Provenance.Bounds = &bounds
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package decide
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestHash(t *testing.T) {
+	input := Generate(7)
+	hash, err := Hash(input)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// a document with another layout decodes to the same input
+	content, _ := json.MarshalIndent(input, "", "    ")
+	var decoded INPUT
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		t.Error(err)
+		return
+	}
+	if other, _ := Hash(decoded); other != hash {
+		t.Error("Expected the same hash after a round trip")
+		return
+	}
+
+	input.Points[0][0] += 1
+	if other, _ := Hash(input); other == hash {
+		t.Error("Expected a different hash for a different input")
+		return
+	}
+}
+
+func TestSetProvenance(t *testing.T) {
+	input := Generate(8)
+	decide := Decide{}
+	if err := decide.Decide(input); err != nil {
+		t.Error(err)
+		return
+	}
+	timestamp := time.Unix(1500000000, 0)
+	if err := decide.SetProvenance("input/input8.json", timestamp); err != nil {
+		t.Error(err)
+		return
+	}
+	hash, _ := Hash(input)
+	p := decide.Provenance
+	if p.Input != "input/input8.json" || p.InputSHA256 != hash || p.EngineVersion != Version ||
+		p.Mode != ModeSequential || !p.Timestamp.Equal(timestamp) || *p.RandomSeed != 8 {
+		t.Error("Unexpected provenance", p)
+		return
+	}
+
+	if p.MaxPoints != 0 || p.Bounds != nil {
+		t.Error("Expected the default limits of the engine to be omitted", p)
+		return
+	}
+
+	e := Engine{MaxPoints: 500, Bounds: &Bounds{Min: [2]float64{-1e6, -1e6}, Max: [2]float64{1e6, 1e6}}, Evaluation: EvaluationStrict, Concurrency: 3}
+	decide, _ = e.Decide(context.Background(), input)
+	if err := decide.SetProvenance("-", timestamp); err != nil {
+		t.Error(err)
+		return
+	}
+	replay := decide.Provenance.Engine()
+	if replay.MaxPoints != 500 || *replay.Bounds != *e.Bounds || replay.Evaluation != EvaluationStrict || replay.mode() != ModeConcurrent {
+		t.Error("Expected the engine in the provenance, got", decide.Provenance)
+		return
+	}
+
+	if err := decide.Decide(input); err != nil {
+		t.Error(err)
+		return
+	}
+	if decide.Provenance != nil {
+		t.Error("Expected Decide to reset the provenance")
+		return
+	}
+}
@@ -0,0 +1,287 @@
+package decide
+
+import "math"
+
+// Stream evaluates the LICs over a sliding window of the last points of
+// a track, received one at a time. After each point its decision is the
+// decision of its engine on the points of the window; while the window fills
+// up, a LIC whose windows do not fit yet is not met.
+//
+// Each LIC is a search for a set of consecutive points that satisfies a
+// condition. Instead of rescanning the window, the stream evaluates the
+// only new set of each LIC when a point arrives, and forgets the set
+// that starts at the point that leaves the window.
+type Stream struct {
+	input    INPUT
+	capacity int
+	// points is a ring buffer of the last capacity points, pushed is the
+	// number of points pushed since the start of the track.
+	points [][2]float64
+	pushed int
+	lics   [NB_LIC]licState
+	// geometry is the geometry of the evaluation of the engine.
+	geometry geometry
+	decision Decide
+}
+
+// licState holds the start of the sets of points of a LIC in the window
+// that satisfy each of its conditions, in order.
+type licState struct {
+	marks [2][]int
+}
+
+func (s *licState) mark(cond int, start int) {
+	s.marks[cond] = append(s.marks[cond], start)
+}
+
+// forget drops the sets that start before start.
+func (s *licState) forget(start int) {
+	for c := range s.marks {
+		for len(s.marks[c]) > 0 && s.marks[c][0] < start {
+			s.marks[c] = s.marks[c][1:]
+		}
+	}
+}
+
+// NewStream returns a stream that evaluates the LCM, PUV and PARAMETERS
+// of input over a window of the last capacity points. The points of
+// input are ignored; the constraints of the parameters are checked as
+// if NUMPOINTS was capacity.
+func NewStream(input INPUT, capacity int) (*Stream, error) {
+	var e Engine
+	return e.NewStream(input, capacity)
+}
+
+// NewStream is like the NewStream function, with the maximum number of
+// points of e as the maximum capacity. The points pushed are not checked
+// against the bounds of e, see Bounds.Check.
+func (e *Engine) NewStream(input INPUT, capacity int) (*Stream, error) {
+	config := input
+	config.NumPoints = capacity
+	config.Points = make([][2]float64, capacity)
+	unbounded := *e
+	unbounded.Bounds = nil
+	if err := unbounded.Validate(config); err != nil {
+		return nil, err
+	}
+	config.Points = nil
+	g, err := e.evaluation().geometry()
+	if err != nil {
+		return nil, err
+	}
+	s := &Stream{
+		input:    config,
+		capacity: capacity,
+		points:   make([][2]float64, capacity),
+		geometry: g,
+	}
+	s.decision.input = config
+	s.decision.evaluation = e.evaluation()
+	s.update(Cmv{})
+	return s, nil
+}
+
+// at returns the point pushed at index i, which must be in the window.
+func (s *Stream) at(i int) [2]float64 {
+	return s.points[i % s.capacity]
+}
+
+// Len returns the number of points in the window.
+func (s *Stream) Len() int {
+	return min(s.pushed, s.capacity)
+}
+
+// Decision returns the decision on the points of the window.
+func (s *Stream) Decision() Decide {
+	return s.decision
+}
+
+// Push adds a point to the window, evicting the oldest one when the
+// window is full. It returns the new decision and whether its CMV, FUV
+// or LAUNCH changed. The point must have finite coordinates.
+func (s *Stream) Push(p [2]float64) (Decide, bool) {
+	params := s.input.Parameters
+	s.points[s.pushed % s.capacity] = p
+	s.pushed++
+	first := s.pushed - s.Len()
+
+	var cmv Cmv
+	for lic := 0; lic < NB_LIC; lic++ {
+		state := &s.lics[lic]
+		state.forget(first)
+		span := licSpan(lic, params)
+		start := s.pushed - span
+		if span >= 2 && start >= first {
+			a, b := licSets[lic](s, params, s.geometry, start)
+			if a {
+				state.mark(0, start)
+			}
+			if b {
+				state.mark(1, start)
+			}
+		}
+		cmv[lic] = state.value(lic) && s.Len() >= licMinPoints[lic]
+	}
+	return s.decision, s.update(cmv)
+}
+
+// update recomputes the decision from cmv and reports whether it changed.
+func (s *Stream) update(cmv Cmv) bool {
+	d := &s.decision
+	previous := *d
+	d.CMV = cmv
+	d.performPUM()
+	d.performFUV()
+	d.isToLaunch()
+	return previous.CMV != d.CMV || previous.FUV != d.FUV || previous.Launch != d.Launch
+}
+
+// decided reports whether the sets marked so far decide the value of the
+// LIC lic, whatever the following sets.
+func (s *licState) decided(lic int) bool {
+	switch lic {
+	case 2, 9:
+		return len(s.marks[0]) > 0 || len(s.marks[1]) > 0
+	}
+	return s.value(lic)
+}
+
+func (s *licState) value(lic int) bool {
+	switch lic {
+	case 2, 9:
+		// The scan stops at the first set with an undefined angle.
+		return len(s.marks[0]) > 0 && (len(s.marks[1]) == 0 || s.marks[0][0] < s.marks[1][0])
+	case 12, 13, 14:
+		return len(s.marks[0]) > 0 && len(s.marks[1]) > 0
+	}
+	return len(s.marks[0]) > 0
+}
+
+// licMinPoints is the number of points under which a LIC is not met.
+var licMinPoints = [NB_LIC]int{0, 0, 0, 0, 0, 0, 3, 3, 5, 5, 5, 3, 3, 5, 5}
+
+// licSpan returns the number of consecutive points covered by a set of
+// points of a LIC, from its first to its last point.
+func licSpan(lic int, p Parameters) int {
+	switch lic {
+	case 0, 5:
+		return 2
+	case 1, 2, 3:
+		return 3
+	case 4:
+		return p.Q_PTS
+	case 6:
+		return p.N_PTS
+	case 7, 12:
+		return p.K_PTS + 2
+	case 8, 13:
+		return gapsSpan(p.A_PTS, p.B_PTS)
+	case 9:
+		return gapsSpan(p.C_PTS, p.D_PTS)
+	case 10, 14:
+		return gapsSpan(p.E_PTS, p.F_PTS)
+	case 11:
+		return p.G_PTS + 2
+	}
+	return 0
+}
+
+// gapsSpan returns the number of points covered by a set of three points
+// separated by a and b points, or 0 when the number overflows: such a set
+// fits in no input.
+func gapsSpan(a int, b int) int {
+	if b > 0 && a > math.MaxInt - 3 - b {
+		return 0
+	}
+	return a + b + 3
+}
+
+// points gives the points of a track by their index from its start.
+type points interface {
+	at(i int) [2]float64
+}
+
+// licSets evaluates the set of points of a LIC that starts at start with
+// a geometry. For LICs 12 to 14 the results are the two parts of the
+// condition; for LICs 2 and 9 the second result reports an undefined
+// angle.
+var licSets = [NB_LIC]func(s points, p Parameters, g geometry, start int) (bool, bool){
+	func(s points, p Parameters, g geometry, i int) (bool, bool) {
+		return g.longer(s.at(i), s.at(i + 1), p.LENGTH1), false
+	},
+	func(s points, p Parameters, g geometry, i int) (bool, bool) {
+		return g.outside(s.at(i), s.at(i + 1), s.at(i + 2), p.RADIUS1), false
+	},
+	func(s points, p Parameters, g geometry, i int) (bool, bool) {
+		return g.angle(s.at(i), s.at(i + 1), s.at(i + 2), p.EPSILON)
+	},
+	func(s points, p Parameters, g geometry, i int) (bool, bool) {
+		return g.larger(s.at(i), s.at(i + 1), s.at(i + 2), p.AREA1), false
+	},
+	func(s points, p Parameters, g geometry, i int) (bool, bool) {
+		var quadrants [4]bool
+		used := 0
+		for j := i; j < i + p.Q_PTS; j++ {
+			q := getQuadranNumber(s.at(j))
+			if !quadrants[q] {
+				quadrants[q] = true
+				used++
+			}
+		}
+		return used > p.QUADS, false
+	},
+	func(s points, p Parameters, g geometry, i int) (bool, bool) {
+		return g.decreasing(s.at(i), s.at(i + 1)), false
+	},
+	func(s points, p Parameters, g geometry, i int) (bool, bool) {
+		n := p.N_PTS
+		p1 := s.at(i)
+		p2 := s.at(i + n - 1)
+		if g.coincident(p1, p2) {
+			for j := i; j < i + n; j++ {
+				if g.longer(s.at(j), p1, p.DIST) {
+					return true, false
+				}
+			}
+			return false, false
+		}
+		for j := i + 1; j < i + n - 1; j++ {
+			if g.farFromLine(s.at(j), p1, p2, p.DIST) {
+				return true, false
+			}
+		}
+		return false, false
+	},
+	func(s points, p Parameters, g geometry, i int) (bool, bool) {
+		return g.longer(s.at(i), s.at(i + p.K_PTS + 1), p.LENGTH1), false
+	},
+	func(s points, p Parameters, g geometry, i int) (bool, bool) {
+		return g.outside(s.at(i), s.at(i + p.A_PTS + 1), s.at(i + p.A_PTS + p.B_PTS + 2), p.RADIUS1), false
+	},
+	func(s points, p Parameters, g geometry, i int) (bool, bool) {
+		return g.angle(s.at(i), s.at(i + p.C_PTS + 1), s.at(i + p.C_PTS + p.D_PTS + 2), p.EPSILON)
+	},
+	func(s points, p Parameters, g geometry, i int) (bool, bool) {
+		return g.larger(s.at(i), s.at(i + p.E_PTS + 1), s.at(i + p.E_PTS + p.F_PTS + 2), p.AREA1), false
+	},
+	func(s points, p Parameters, g geometry, i int) (bool, bool) {
+		return g.decreasing(s.at(i), s.at(i + p.G_PTS + 1)), false
+	},
+	func(s points, p Parameters, g geometry, i int) (bool, bool) {
+		p1 := s.at(i)
+		p2 := s.at(i + p.K_PTS + 1)
+		return g.longer(p1, p2, p.LENGTH1), g.shorter(p1, p2, p.LENGTH2)
+	},
+	func(s points, p Parameters, g geometry, i int) (bool, bool) {
+		p1 := s.at(i)
+		p2 := s.at(i + p.A_PTS + 1)
+		p3 := s.at(i + p.A_PTS + p.B_PTS + 2)
+		return g.outside(p1, p2, p3, p.RADIUS1), g.inside(p1, p2, p3, p.RADIUS2)
+	},
+	func(s points, p Parameters, g geometry, i int) (bool, bool) {
+		p1 := s.at(i)
+		p2 := s.at(i + p.E_PTS + 1)
+		p3 := s.at(i + p.E_PTS + p.F_PTS + 2)
+		return g.larger(p1, p2, p3, p.AREA1), g.smaller(p1, p2, p3, p.AREA2)
+	},
+}
@@ -0,0 +1,96 @@
+package decide
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// window returns input with the last n of its first end points.
+func window(input INPUT, end int, n int) INPUT {
+	start := max(0, end - n)
+	input.Points = input.Points[start:end]
+	input.NumPoints = len(input.Points)
+	return input
+}
+
+func TestStream(t *testing.T) {
+	for seed := int64(0); seed < 200; seed++ {
+		input := Generate(seed)
+		if seed % 2 == 0 {
+			// Coincident points, for the undefined angles of LICs 2 and 9.
+			for i := range input.Points {
+				input.Points[i][0] = math.Round(input.Points[i][0] / 5e5)
+				input.Points[i][1] = math.Round(input.Points[i][1] / 5e5)
+			}
+			input.Parameters.LENGTH1 = 1
+			input.Parameters.RADIUS1 = 0.5
+			input.Parameters.AREA1 = 0.5
+		}
+		capacity := input.NumPoints
+		if seed % 3 == 0 {
+			capacity = (capacity + 1) / 2 + 4
+		}
+		engine := Engine{Evaluation: Evaluations[seed % int64(len(Evaluations))]}
+		stream, err := engine.NewStream(input, capacity)
+		if err != nil {
+			if Validate(window(input, input.NumPoints, capacity)) == nil {
+				t.Error("Unexpected error for seed", seed, err)
+				return
+			}
+			continue
+		}
+
+		previous := stream.Decision()
+		for i, p := range input.Points {
+			d, changed := stream.Push(p)
+			if changed != (Compare(previous, d) != nil) {
+				t.Error("Expected the change of decision to be reported for seed", seed, "at point", i)
+				return
+			}
+			previous = d
+
+			expected, err := engine.Decide(context.Background(), window(input, i + 1, capacity))
+			if err != nil {
+				if stream.Len() == capacity {
+					t.Error("Unexpected error for seed", seed, "at point", i, err)
+					return
+				}
+				continue
+			}
+			if diffs := Compare(expected, d); diffs != nil {
+				t.Error("The stream and Decide disagree for seed", seed, "at point", i, engine.Evaluation, diffs)
+				return
+			}
+		}
+	}
+}
+
+func TestStreamEvict(t *testing.T) {
+	input := INPUT{}
+	input.NumPoints = 3
+	input.Parameters.LENGTH1 = 5
+	input.Parameters.K_PTS = 1
+	input.Parameters.G_PTS = 1
+	input.Parameters.Q_PTS = 2
+	input.Parameters.QUADS = 1
+	input.Parameters.N_PTS = 3
+	input.LCM = Generate(0).LCM
+	stream, err := NewStream(input, 3)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	stream.Push([2]float64{0, 0})
+	d, _ := stream.Push([2]float64{10, 0})
+	if !d.CMV[0] {
+		t.Error("Expected LIC 0 to be met by 2 points 10 apart")
+		return
+	}
+	stream.Push([2]float64{10, 1})
+	d, changed := stream.Push([2]float64{10, 2})
+	if d.CMV[0] || !changed {
+		t.Error("Expected LIC 0 not to be met once the first point left the window")
+		return
+	}
+}
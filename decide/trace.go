@@ -0,0 +1,85 @@
+package decide
+
+import (
+	"time"
+)
+
+// Trace records how a decision was derived, for post-mortem analysis.
+// It is attached to the result when Engine.Trace is set.
+type Trace struct {
+	Rules []RuleTrace `json:"RULES"`
+	PUM   []PUMStep   `json:"PUM"`
+	FUV   []FUVStep   `json:"FUV"`
+}
+
+// RuleTrace records the evaluation of a LIC.
+type RuleTrace struct {
+	LIC   int       `json:"LIC"`
+	Start time.Time `json:"START"`
+	End   time.Time `json:"END"`
+	// Windows is the number of sets of points examined.
+	Windows int `json:"WINDOWS"`
+	// ExitIndex is the index of the first point of the set that
+	// satisfied the LIC and stopped the scan, -1 if none did.
+	ExitIndex int    `json:"EXIT_INDEX"`
+	Value     bool   `json:"VALUE"`
+	Error     string `json:"ERROR,omitempty"`
+}
+
+// PUMStep records the derivation of PUM[I][J] from the LCM and the CMV.
+type PUMStep struct {
+	I     int     `json:"I"`
+	J     int     `json:"J"`
+	LCM   Command `json:"LCM"`
+	CMV   [2]bool `json:"CMV"`
+	Value bool    `json:"VALUE"`
+}
+
+// FUVStep records the derivation of FUV[I]. Blocking is the first
+// false PUM entry of the row, -1 if none.
+type FUVStep struct {
+	I        int  `json:"I"`
+	PUV      bool `json:"PUV"`
+	Blocking int  `json:"BLOCKING"`
+	Value    bool `json:"VALUE"`
+}
+
+// scan follows the windows examined by the rule being evaluated.
+type scan struct {
+	windows int
+	last    int
+	exit    int
+}
+
+// examineContext is the number of sets of points between two checks of
+// the context of the decision by a rule.
+const examineContext = 1024
+
+// examine records that the rule examines the set of points starting at i.
+// Every examineContext sets, it reports whether the context of the
+// decision is done, in which case the rule stops with its error.
+func (d Decide) examine(i int) bool {
+	if d.scan != nil {
+		d.scan.windows++
+		d.scan.last = i
+	}
+	return d.ctx != nil && i % examineContext == 0 && d.ctx.Err() != nil
+}
+
+func (t *Trace) addRule(lic int, start time.Time, end time.Time, s *scan, value bool, err error) {
+	rule := RuleTrace{
+		LIC:       lic,
+		Start:     start,
+		End:       end,
+		Windows:   s.windows,
+		ExitIndex: -1,
+		Value:     value,
+	}
+	if value && s.windows > 0 {
+		rule.ExitIndex = s.last
+	}
+	if err != nil {
+		rule.Error = err.Error()
+	}
+	t.Rules = append(t.Rules, rule)
+}
@@ -0,0 +1,366 @@
+package decide
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ValidationError reports an input that does not respect the
+// specification, either in its structure or in the constraints
+// on its parameters.
+type ValidationError struct {
+	// Field is the name of the offending input field, e.g. NUMPOINTS or Q_PTS.
+	Field string
+	Msg   string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Msg
+}
+
+func invalid(field string, msg string) error {
+	return &ValidationError{Field: field, Msg: msg}
+}
+
+// Validate checks the structure of the input and the constraints
+// of the parameters of every LIC without evaluating any of them.
+func Validate(input INPUT) error {
+	var e Engine
+	return e.Validate(input)
+}
+
+// Validate is like the Validate function, with the maximum number of
+// points of e.
+func (e *Engine) Validate(input INPUT) error {
+	if err := e.checkInput(input); err != nil {
+		return err
+	}
+	if err := checkLCM(input); err != nil {
+		return err
+	}
+	for i := 0; i < NB_LIC; i++ {
+		if err := ruleConstraints[i](input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkInput checks the number of points, their coordinates and the
+// parameters that are numbers.
+func (e *Engine) checkInput(input INPUT) error {
+	if b := e.Bounds; b != nil && (b.Min[0] > b.Max[0] || b.Min[1] > b.Max[1]) {
+		return fmt.Errorf("invalid bounds %v %v, the minimum is greater than the maximum", b.Min, b.Max)
+	}
+	if err := checkPoints(input, e.maxPoints()); err != nil {
+		return err
+	}
+	for i, p := range input.Points {
+		if err := e.Bounds.Check(i, p); err != nil {
+			return err
+		}
+	}
+	return checkFinite(input.Parameters)
+}
+
+// PointError reports a point with a coordinate that is not a finite
+// number or that is out of the bounds of the engine.
+type PointError struct {
+	// Index is the index of the point in POINTS, from 0.
+	Index int
+	Point [2]float64
+	Msg   string
+}
+
+func (e *PointError) Error() string {
+	return fmt.Sprintf("Invalid point %d %v: %s", e.Index, e.Point, e.Msg)
+}
+
+// Unwrap returns the ValidationError of the point, on POINTS.
+func (e *PointError) Unwrap() error {
+	return invalid("POINTS", e.Error())
+}
+
+// Bounds is a bounding box of the coordinates of the points, edges
+// included. Min and Max may be equal, bounding the points to a line or to
+// a single point.
+type Bounds struct {
+	Min [2]float64 `json:"MIN"`
+	Max [2]float64 `json:"MAX"`
+}
+
+// Check returns a PointError when a coordinate of the point p of index i
+// is NaN, infinite, or out of b. A nil b does not bound the points.
+func (b *Bounds) Check(i int, p [2]float64) error {
+	for _, c := range p {
+		if math.IsNaN(c) || math.IsInf(c, 0) {
+			return &PointError{Index: i, Point: p, Msg: "not a finite number"}
+		}
+	}
+	if b == nil {
+		return nil
+	}
+	for k := range p {
+		if p[k] < b.Min[k] || p[k] > b.Max[k] {
+			return &PointError{Index: i, Point: p, Msg: fmt.Sprintf("out of the bounds %v %v", b.Min, b.Max)}
+		}
+	}
+	return nil
+}
+
+// checkFinite rejects the parameters that are NaN or infinite, which
+// would make every comparison with them silently false.
+func checkFinite(p Parameters) error {
+	values := []struct {
+		field string
+		value float64
+	}{
+		{"RADIUS1", p.RADIUS1}, {"RADIUS2", p.RADIUS2},
+		{"LENGTH1", p.LENGTH1}, {"LENGTH2", p.LENGTH2},
+		{"DIST", p.DIST}, {"EPSILON", p.EPSILON},
+		{"AREA1", p.AREA1}, {"AREA2", p.AREA2},
+	}
+	for _, v := range values {
+		if math.IsNaN(v.value) || math.IsInf(v.value, 0) {
+			return invalid(v.field, fmt.Sprintf("Invalid %s, not a finite number.", v.field))
+		}
+	}
+	return nil
+}
+
+func checkPoints(input INPUT, maxPoints int) error {
+	if input.NumPoints < 2 || input.NumPoints > maxPoints {
+		return invalid("NUMPOINTS", "Invalid NumPoints value.")
+	}
+	if (len(input.Points) != input.NumPoints) {
+		return invalid("POINTS", "Invalid NumPoints value different from the actual number of points.")
+	}
+	return nil
+}
+
+func checkLCM(input INPUT) error {
+	for i := 0; i < NB_LIC; i++ {
+		row, ok := input.LCM[strconv.Itoa(i)]
+		if !ok {
+			return invalid("LCM", fmt.Sprintf("Missing LCM row %d.", i))
+		}
+		for j, c := range row {
+			if c != ANDD && c != ORR && c != NOTUSED {
+				return invalid("LCM", fmt.Sprintf("Invalid LCM[%d][%d] value %q.", i, j, c))
+			}
+		}
+	}
+	return nil
+}
+
+// ruleConstraints holds, for each LIC, the checks on the parameters
+// that must pass before the rule can be evaluated.
+var ruleConstraints = [NB_LIC]func(INPUT) error{
+	checkRule0,
+	checkRule1,
+	checkRule2,
+	checkRule3,
+	checkRule4,
+	checkRule5,
+	checkRule6,
+	checkRule7,
+	checkRule8,
+	checkRule9,
+	checkRule10,
+	checkRule11,
+	checkRule12,
+	checkRule13,
+	checkRule14,
+}
+
+func checkRule0(input INPUT) error {
+	// (0 ≤ LENGTH1)
+	if input.Parameters.LENGTH1 < 0 {
+		return invalid("LENGTH1", "Invalid length1")
+	}
+	return nil
+}
+
+func checkRule1(input INPUT) error {
+	// (0 ≤ RADIUS1)
+	if input.Parameters.RADIUS1 < 0 {
+		return invalid("RADIUS1", "Invalid RADIUS1")
+	}
+	return nil
+}
+
+func checkRule2(input INPUT) error {
+	// (0 ≤ EPSILON < PI)
+	if input.Parameters.EPSILON < 0 || input.Parameters.EPSILON >= math.Pi {
+		return invalid("EPSILON", "Invalid EPSILON")
+	}
+	return nil
+}
+
+func checkRule3(input INPUT) error {
+	// (0 ≤ AREA1)
+	if input.Parameters.AREA1 < 0 {
+		return invalid("AREA1", "Invalid AREA1")
+	}
+	return nil
+}
+
+func checkRule4(input INPUT) error {
+	// (2 ≤ Q PTS ≤ NUMPOINTS)
+	if input.Parameters.Q_PTS < 2 || input.Parameters.Q_PTS > input.NumPoints {
+		return invalid("Q_PTS", "Invalid Q_PTS")
+	}
+	// (1 ≤ QUADS ≤ 3)
+	if input.Parameters.QUADS < 1 || input.Parameters.QUADS > 3 {
+		return invalid("QUADS", "Invalid QUADS")
+	}
+	return nil
+}
+
+func checkRule5(input INPUT) error {
+	return nil
+}
+
+func checkRule6(input INPUT) error {
+	// The condition is not met when NUMPOINTS < 3.
+	if input.NumPoints < 3 {
+		return nil
+	}
+	// (3 ≤ N PTS ≤ NUMPOINTS)
+	if input.Parameters.N_PTS < 3 || input.Parameters.N_PTS > input.NumPoints {
+		return invalid("N_PTS", "Invalid N_PTS.")
+	}
+	// (0 ≤ DIST)
+	if input.Parameters.DIST < 0 {
+		return invalid("DIST", "Invalid DIST.")
+	}
+	return nil
+}
+
+func checkRule7(input INPUT) error {
+	// The condition is not met when NUMPOINTS < 3.
+	if input.NumPoints < 3 {
+		return nil
+	}
+	// 1 ≤ K PTS ≤ (NUMPOINTS−2)
+	if input.Parameters.K_PTS < 1 || input.Parameters.K_PTS > input.NumPoints - 2 {
+		return invalid("K_PTS", "Invalid K_PTS.")
+	}
+	return nil
+}
+
+func checkRule8(input INPUT) error {
+	// The condition is not met when NUMPOINTS < 5.
+	if input.NumPoints < 5 {
+		return nil
+	}
+	// 1 ≤ A PTS
+	if input.Parameters.A_PTS < 1 {
+		return invalid("A_PTS", "Invalid A_PTS.")
+	}
+	// 1 ≤ B PTS
+	if input.Parameters.B_PTS < 1 {
+		return invalid("B_PTS", "Invalid B_PTS.")
+	}
+	// A PTS+B PTS ≤ (NUMPOINTS−3), without overflowing the sum.
+	if input.Parameters.A_PTS > input.NumPoints - 3 - input.Parameters.B_PTS {
+		return invalid("A_PTS", "Invalid A_PTS, B_PTS.")
+	}
+	return nil
+}
+
+func checkRule9(input INPUT) error {
+	// When NUMPOINTS < 5, the condition is not met.
+	if input.NumPoints < 5 {
+		return nil
+	}
+	// 1 ≤ C PTS
+	if input.Parameters.C_PTS < 1 {
+		return invalid("C_PTS", "Invalid C_PTS.")
+	}
+	// 1 ≤ D PTS
+	if input.Parameters.D_PTS < 1 {
+		return invalid("D_PTS", "Invalid D_PTS.")
+	}
+	return nil
+}
+
+func checkRule10(input INPUT) error {
+	// The condition is not met when NUMPOINTS < 5.
+	if input.NumPoints < 5 {
+		return nil
+	}
+	// 1 ≤ E PTS
+	if input.Parameters.E_PTS < 1 {
+		return invalid("E_PTS", "Invalid E_PTS.")
+	}
+	// 1 ≤ F PTS
+	if input.Parameters.F_PTS < 1 {
+		return invalid("F_PTS", "Invalid F_PTS.")
+	}
+	return nil
+}
+
+func checkRule11(input INPUT) error {
+	// The condition is not met when NUMPOINTS < 3.
+	if input.NumPoints < 3 {
+		return nil
+	}
+	// 1 ≤ G PTS
+	if input.Parameters.G_PTS < 1 {
+		return invalid("G_PTS", "Invalid G_PTS.")
+	}
+	return nil
+}
+
+func checkRule12(input INPUT) error {
+	// The condition is not met when NUMPOINTS < 3.
+	if input.NumPoints < 3 {
+		return nil
+	}
+	// The same K PTS as LIC 7, checked here too when the LICs
+	// are evaluated concurrently.
+	if err := checkRule7(input); err != nil {
+		return err
+	}
+	// 0 ≤ LENGTH2
+	if input.Parameters.LENGTH2 < 0 {
+		return invalid("LENGTH2", "Invalid LENGTH2.")
+	}
+	return nil
+}
+
+func checkRule13(input INPUT) error {
+	// The condition is not met when NUMPOINTS < 5.
+	if input.NumPoints < 5 {
+		return nil
+	}
+	// The same A PTS and B PTS as LIC 8, checked here too when the LICs
+	// are evaluated concurrently.
+	if err := checkRule8(input); err != nil {
+		return err
+	}
+	// 0 ≤ RADIUS2
+	if input.Parameters.RADIUS2 < 0 {
+		return invalid("RADIUS2", "Invalid RADIUS2.")
+	}
+	return nil
+}
+
+func checkRule14(input INPUT) error {
+	// The condition is not met when NUMPOINTS < 5.
+	if input.NumPoints < 5 {
+		return nil
+	}
+	// The same E PTS and F PTS as LIC 10, checked here too when the LICs
+	// are evaluated concurrently.
+	if err := checkRule10(input); err != nil {
+		return err
+	}
+	// 0 ≤ AREA2
+	if input.Parameters.AREA2 < 0 {
+		return invalid("AREA2", "Invalid AREA2.")
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package decide
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	input := Generate(1)
+	if err := Validate(input); err != nil {
+		t.Error(err)
+		return
+	}
+
+	input.Parameters.Q_PTS = 1
+	err := Validate(input)
+	if err == nil {
+		t.Error("Invalid Q_PTS expected")
+		return
+	}
+	verr, ok := err.(*ValidationError)
+	if !ok || verr.Field != "Q_PTS" {
+		t.Error("Expected a ValidationError on Q_PTS, got", err)
+		return
+	}
+
+	input = Generate(1)
+	input.Parameters.G_PTS = -2
+	err = Validate(input)
+	if verr, ok := err.(*ValidationError); !ok || verr.Field != "G_PTS" {
+		t.Error("Expected a ValidationError on G_PTS, got", err)
+		return
+	}
+
+	input = Generate(1)
+	delete(input.LCM, "3")
+	err = Validate(input)
+	if verr, ok := err.(*ValidationError); !ok || verr.Field != "LCM" {
+		t.Error("Expected a ValidationError on LCM, got", err)
+		return
+	}
+
+	input = Generate(1)
+	input.Points = input.Points[1:]
+	err = Validate(input)
+	if verr, ok := err.(*ValidationError); !ok || verr.Field != "POINTS" {
+		t.Error("Expected a ValidationError on POINTS, got", err)
+		return
+	}
+}
+
+func TestValidateOverflow(t *testing.T) {
+	input := Generate(1)
+	input.Parameters.A_PTS, input.Parameters.B_PTS = math.MaxInt64, math.MaxInt64
+	err := Validate(input)
+	if verr, ok := err.(*ValidationError); !ok || verr.Field != "A_PTS" {
+		t.Error("Expected a ValidationError on A_PTS, got", err)
+		return
+	}
+	e := Engine{Concurrency: 4}
+	if _, err := e.Decide(context.Background(), input); err == nil {
+		t.Error("Expected the concurrent engine to reject A_PTS")
+		return
+	}
+
+	// Like C PTS+D PTS and E PTS+F PTS greater than NUMPOINTS−3, sums that
+	// overflow do not meet LICs 9, 10 and 14.
+	input = Generate(1)
+	input.Parameters.C_PTS, input.Parameters.D_PTS = math.MaxInt64, math.MaxInt64
+	input.Parameters.E_PTS, input.Parameters.F_PTS = math.MaxInt64, math.MaxInt64
+	if err := Validate(input); err != nil {
+		t.Error(err)
+		return
+	}
+	for _, e := range []Engine{{}, {Concurrency: 4}, {Evaluation: EvaluationStrict}} {
+		d, err := e.Decide(context.Background(), input)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if d.CMV[9] || d.CMV[10] || d.CMV[14] {
+			t.Error("Expected LICs 9, 10 and 14 not to be met, got", d.CMV)
+			return
+		}
+	}
+}
+
+func TestValidateMatchesDecide(t *testing.T) {
+	for seed := int64(0); seed < 50; seed++ {
+		input := Generate(seed)
+		input.Parameters.C_PTS = int(seed % 5) - 2
+		input.Parameters.N_PTS = int(seed % 7)
+		decide := Decide{}
+		errDecide := decide.Decide(input)
+		errValidate := Validate(input)
+		if (errDecide == nil) != (errValidate == nil) {
+			t.Error("Validate and Decide disagree for seed", seed, errDecide, errValidate)
+			return
+		}
+	}
+}
+
+func TestValidateFinite(t *testing.T) {
+	input := Generate(1)
+	input.Points[2][1] = math.Inf(-1)
+	err := Validate(input)
+	var perr *PointError
+	if !errors.As(err, &perr) || perr.Index != 2 {
+		t.Error("Expected a PointError on point 2, got", err)
+		return
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Field != "POINTS" {
+		t.Error("Expected a ValidationError on POINTS, got", err)
+		return
+	}
+	decide := Decide{}
+	if err := decide.Decide(input); !errors.As(err, &perr) {
+		t.Error("Expected Decide to reject the point, got", err)
+		return
+	}
+
+	input = Generate(1)
+	input.Parameters.AREA1 = math.NaN()
+	err = Validate(input)
+	if !errors.As(err, &verr) || verr.Field != "AREA1" {
+		t.Error("Expected a ValidationError on AREA1, got", err)
+		return
+	}
+}
+
+func TestBounds(t *testing.T) {
+	input := Generate(1)
+	for i := range input.Points {
+		input.Points[i] = [2]float64{float64(i), -5}
+	}
+	input.Points[4] = [2]float64{120, 5}
+	engine := Engine{Bounds: &Bounds{Min: [2]float64{-10, -10}, Max: [2]float64{100, 100}}}
+	err := engine.Validate(input)
+	var perr *PointError
+	if !errors.As(err, &perr) || perr.Index != 4 {
+		t.Error("Expected a PointError on point 4, got", err)
+		return
+	}
+	input.Points[4] = [2]float64{100, -10}
+	if err := engine.Validate(input); err != nil {
+		t.Error("Expected the edges to be in the bounds, got", err)
+		return
+	}
+	if err := Validate(input); err != nil {
+		t.Error("Expected no Bounds not to bound the points, got", err)
+		return
+	}
+
+	// a box of a single point is not the absence of bounds
+	engine.Bounds = &Bounds{}
+	if err := engine.Validate(input); !errors.As(err, &perr) || perr.Index != 0 {
+		t.Error("Expected a PointError on point 0, got", err)
+		return
+	}
+	input.Points = make([][2]float64, input.NumPoints)
+	if err := engine.Validate(input); err != nil {
+		t.Error("Expected the points at the origin to be in the bounds, got", err)
+		return
+	}
+
+	engine.Bounds = &Bounds{Min: [2]float64{1, 0}, Max: [2]float64{0, 0}}
+	if err := engine.Validate(input); err == nil {
+		t.Error("Expected inverted bounds to be rejected")
+		return
+	}
+}
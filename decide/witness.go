@@ -0,0 +1,71 @@
+package decide
+
+// Witness is a set of points that satisfies a LIC.
+type Witness struct {
+	LIC int `json:"LIC"`
+	// Points are the indices of the points of the set in the order of
+	// the definition of the LIC: the vertex of an angle is second, and
+	// for LICs 4 and 6 every point of the set is listed.
+	Points []int `json:"POINTS"`
+	// Second is the set that satisfies the second condition of LICs 12
+	// to 14, which may differ from Points.
+	Second []int `json:"SECOND,omitempty"`
+}
+
+// Witnesses returns a witness for each LIC met by the decision: the first
+// set of points that satisfies it, with the evaluation of the decision.
+// A decision that was not made by Decide, e.g. decoded from JSON, has no
+// witness.
+func (d Decide) Witnesses() []Witness {
+	g := d.geometry
+	if g == nil {
+		g = floatGeometry{}
+	}
+	var witnesses []Witness
+	for lic := 0; lic < NB_LIC; lic++ {
+		if !d.CMV[lic] || d.input.NumPoints != len(d.input.Points) {
+			continue
+		}
+		state := d.scanSets(lic, g, false)
+		if len(state.marks[0]) == 0 {
+			continue
+		}
+		w := Witness{LIC: lic, Points: licPoints(lic, d.input.Parameters, state.marks[0][0])}
+		if lic >= 12 {
+			if len(state.marks[1]) == 0 {
+				continue
+			}
+			w.Second = licPoints(lic, d.input.Parameters, state.marks[1][0])
+		}
+		witnesses = append(witnesses, w)
+	}
+	return witnesses
+}
+
+// licPoints returns the indices of the points of the set of the LIC lic
+// that starts at start.
+func licPoints(lic int, p Parameters, start int) []int {
+	switch lic {
+	case 0, 5:
+		return []int{start, start + 1}
+	case 1, 2, 3:
+		return []int{start, start + 1, start + 2}
+	case 4, 6:
+		indices := make([]int, licSpan(lic, p))
+		for i := range indices {
+			indices[i] = start + i
+		}
+		return indices
+	case 7, 12:
+		return []int{start, start + p.K_PTS + 1}
+	case 8, 13:
+		return []int{start, start + p.A_PTS + 1, start + p.A_PTS + p.B_PTS + 2}
+	case 9:
+		return []int{start, start + p.C_PTS + 1, start + p.C_PTS + p.D_PTS + 2}
+	case 10, 14:
+		return []int{start, start + p.E_PTS + 1, start + p.E_PTS + p.F_PTS + 2}
+	case 11:
+		return []int{start, start + p.G_PTS + 1}
+	}
+	return nil
+}
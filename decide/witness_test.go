@@ -0,0 +1,76 @@
+package decide
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWitnesses(t *testing.T) {
+	for seed := int64(0); seed < 200; seed++ {
+		for _, input := range []INPUT{Generate(seed), gridInput(seed)} {
+			d := Decide{}
+			if err := d.Decide(input); err != nil {
+				t.Error(err)
+				return
+			}
+			met := 0
+			for _, v := range d.CMV {
+				if v {
+					met++
+				}
+			}
+			witnesses := d.Witnesses()
+			if len(witnesses) != met {
+				t.Error("Expected a witness for each of the", met, "LICs met for seed", seed, "got", witnesses)
+				return
+			}
+			p := input.Parameters
+			at := func(i int) [2]float64 {
+				return input.Points[i]
+			}
+			for _, w := range witnesses {
+				ok := true
+				switch w.LIC {
+				case 0, 7:
+					ok = computeDistancePointToPoint(at(w.Points[0]), at(w.Points[1])) > p.LENGTH1
+				case 3, 10:
+					ok = triangleArea(at(w.Points[0]), at(w.Points[1]), at(w.Points[2])) > p.AREA1
+				case 5, 11:
+					ok = at(w.Points[1])[0] - at(w.Points[0])[0] < 0
+				case 12:
+					ok = computeDistancePointToPoint(at(w.Points[0]), at(w.Points[1])) > p.LENGTH1 &&
+						computeDistancePointToPoint(at(w.Second[0]), at(w.Second[1])) < p.LENGTH2
+				case 14:
+					ok = triangleArea(at(w.Points[0]), at(w.Points[1]), at(w.Points[2])) > p.AREA1 &&
+						triangleArea(at(w.Second[0]), at(w.Second[1]), at(w.Second[2])) < p.AREA2
+				}
+				if !ok || w.Points[len(w.Points) - 1] >= input.NumPoints {
+					t.Error("Expected the witness", w, "to satisfy its LIC for seed", seed)
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestWitnessesDecoded(t *testing.T) {
+	d := Decide{}
+	if err := d.Decide(Generate(2)); err != nil {
+		t.Error(err)
+		return
+	}
+	content, err := json.Marshal(d)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var decoded Decide
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		t.Error(err)
+		return
+	}
+	if witnesses := decoded.Witnesses(); len(witnesses) != 0 {
+		t.Error("Expected a decoded decision to have no witness, got", witnesses)
+		return
+	}
+}
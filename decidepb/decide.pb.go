@@ -0,0 +1,1131 @@
+// Protobuf mirror of the JSON INPUT and result of the decide package.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: decide.proto
+
+package decidepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Command is a connector of the LCM. The names are the JSON strings
+// of decide.Command.
+type Command int32
+
+const (
+	Command_COMMAND_UNSPECIFIED Command = 0
+	Command_ANDD                Command = 1
+	Command_ORR                 Command = 2
+	Command_NOTUSED             Command = 3
+)
+
+// Enum value maps for Command.
+var (
+	Command_name = map[int32]string{
+		0: "COMMAND_UNSPECIFIED",
+		1: "ANDD",
+		2: "ORR",
+		3: "NOTUSED",
+	}
+	Command_value = map[string]int32{
+		"COMMAND_UNSPECIFIED": 0,
+		"ANDD":                1,
+		"ORR":                 2,
+		"NOTUSED":             3,
+	}
+)
+
+func (x Command) Enum() *Command {
+	p := new(Command)
+	*p = x
+	return p
+}
+
+func (x Command) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Command) Descriptor() protoreflect.EnumDescriptor {
+	return file_decide_proto_enumTypes[0].Descriptor()
+}
+
+func (Command) Type() protoreflect.EnumType {
+	return &file_decide_proto_enumTypes[0]
+}
+
+func (x Command) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Command.Descriptor instead.
+func (Command) EnumDescriptor() ([]byte, []int) {
+	return file_decide_proto_rawDescGZIP(), []int{0}
+}
+
+type Point struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             float64                `protobuf:"fixed64,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             float64                `protobuf:"fixed64,2,opt,name=y,proto3" json:"y,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Point) Reset() {
+	*x = Point{}
+	mi := &file_decide_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Point) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Point) ProtoMessage() {}
+
+func (x *Point) ProtoReflect() protoreflect.Message {
+	mi := &file_decide_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Point.ProtoReflect.Descriptor instead.
+func (*Point) Descriptor() ([]byte, []int) {
+	return file_decide_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Point) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Point) GetY() float64 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+type Parameters struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Radius1       float64                `protobuf:"fixed64,1,opt,name=radius1,proto3" json:"radius1,omitempty"`
+	Radius2       float64                `protobuf:"fixed64,2,opt,name=radius2,proto3" json:"radius2,omitempty"`
+	Length1       float64                `protobuf:"fixed64,3,opt,name=length1,proto3" json:"length1,omitempty"`
+	Length2       float64                `protobuf:"fixed64,4,opt,name=length2,proto3" json:"length2,omitempty"`
+	Dist          float64                `protobuf:"fixed64,5,opt,name=dist,proto3" json:"dist,omitempty"`
+	Epsilon       float64                `protobuf:"fixed64,6,opt,name=epsilon,proto3" json:"epsilon,omitempty"`
+	Quads         int32                  `protobuf:"varint,7,opt,name=quads,proto3" json:"quads,omitempty"`
+	Area1         float64                `protobuf:"fixed64,8,opt,name=area1,proto3" json:"area1,omitempty"`
+	Area2         float64                `protobuf:"fixed64,9,opt,name=area2,proto3" json:"area2,omitempty"`
+	APts          int32                  `protobuf:"varint,10,opt,name=a_pts,json=aPts,proto3" json:"a_pts,omitempty"`
+	BPts          int32                  `protobuf:"varint,11,opt,name=b_pts,json=bPts,proto3" json:"b_pts,omitempty"`
+	CPts          int32                  `protobuf:"varint,12,opt,name=c_pts,json=cPts,proto3" json:"c_pts,omitempty"`
+	DPts          int32                  `protobuf:"varint,13,opt,name=d_pts,json=dPts,proto3" json:"d_pts,omitempty"`
+	EPts          int32                  `protobuf:"varint,14,opt,name=e_pts,json=ePts,proto3" json:"e_pts,omitempty"`
+	FPts          int32                  `protobuf:"varint,15,opt,name=f_pts,json=fPts,proto3" json:"f_pts,omitempty"`
+	GPts          int32                  `protobuf:"varint,16,opt,name=g_pts,json=gPts,proto3" json:"g_pts,omitempty"`
+	KPts          int32                  `protobuf:"varint,17,opt,name=k_pts,json=kPts,proto3" json:"k_pts,omitempty"`
+	NPts          int32                  `protobuf:"varint,18,opt,name=n_pts,json=nPts,proto3" json:"n_pts,omitempty"`
+	QPts          int32                  `protobuf:"varint,19,opt,name=q_pts,json=qPts,proto3" json:"q_pts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Parameters) Reset() {
+	*x = Parameters{}
+	mi := &file_decide_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Parameters) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Parameters) ProtoMessage() {}
+
+func (x *Parameters) ProtoReflect() protoreflect.Message {
+	mi := &file_decide_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Parameters.ProtoReflect.Descriptor instead.
+func (*Parameters) Descriptor() ([]byte, []int) {
+	return file_decide_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Parameters) GetRadius1() float64 {
+	if x != nil {
+		return x.Radius1
+	}
+	return 0
+}
+
+func (x *Parameters) GetRadius2() float64 {
+	if x != nil {
+		return x.Radius2
+	}
+	return 0
+}
+
+func (x *Parameters) GetLength1() float64 {
+	if x != nil {
+		return x.Length1
+	}
+	return 0
+}
+
+func (x *Parameters) GetLength2() float64 {
+	if x != nil {
+		return x.Length2
+	}
+	return 0
+}
+
+func (x *Parameters) GetDist() float64 {
+	if x != nil {
+		return x.Dist
+	}
+	return 0
+}
+
+func (x *Parameters) GetEpsilon() float64 {
+	if x != nil {
+		return x.Epsilon
+	}
+	return 0
+}
+
+func (x *Parameters) GetQuads() int32 {
+	if x != nil {
+		return x.Quads
+	}
+	return 0
+}
+
+func (x *Parameters) GetArea1() float64 {
+	if x != nil {
+		return x.Area1
+	}
+	return 0
+}
+
+func (x *Parameters) GetArea2() float64 {
+	if x != nil {
+		return x.Area2
+	}
+	return 0
+}
+
+func (x *Parameters) GetAPts() int32 {
+	if x != nil {
+		return x.APts
+	}
+	return 0
+}
+
+func (x *Parameters) GetBPts() int32 {
+	if x != nil {
+		return x.BPts
+	}
+	return 0
+}
+
+func (x *Parameters) GetCPts() int32 {
+	if x != nil {
+		return x.CPts
+	}
+	return 0
+}
+
+func (x *Parameters) GetDPts() int32 {
+	if x != nil {
+		return x.DPts
+	}
+	return 0
+}
+
+func (x *Parameters) GetEPts() int32 {
+	if x != nil {
+		return x.EPts
+	}
+	return 0
+}
+
+func (x *Parameters) GetFPts() int32 {
+	if x != nil {
+		return x.FPts
+	}
+	return 0
+}
+
+func (x *Parameters) GetGPts() int32 {
+	if x != nil {
+		return x.GPts
+	}
+	return 0
+}
+
+func (x *Parameters) GetKPts() int32 {
+	if x != nil {
+		return x.KPts
+	}
+	return 0
+}
+
+func (x *Parameters) GetNPts() int32 {
+	if x != nil {
+		return x.NPts
+	}
+	return 0
+}
+
+func (x *Parameters) GetQPts() int32 {
+	if x != nil {
+		return x.QPts
+	}
+	return 0
+}
+
+// LcmRow is the row of the LCM of a LIC: 15 connectors.
+type LcmRow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Commands      []Command              `protobuf:"varint,1,rep,packed,name=commands,proto3,enum=decide.v1.Command" json:"commands,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LcmRow) Reset() {
+	*x = LcmRow{}
+	mi := &file_decide_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LcmRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LcmRow) ProtoMessage() {}
+
+func (x *LcmRow) ProtoReflect() protoreflect.Message {
+	mi := &file_decide_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LcmRow.ProtoReflect.Descriptor instead.
+func (*LcmRow) Descriptor() ([]byte, []int) {
+	return file_decide_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LcmRow) GetCommands() []Command {
+	if x != nil {
+		return x.Commands
+	}
+	return nil
+}
+
+type Input struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	NumPoints int32                  `protobuf:"varint,1,opt,name=num_points,json=numPoints,proto3" json:"num_points,omitempty"`
+	Points    []*Point               `protobuf:"bytes,2,rep,name=points,proto3" json:"points,omitempty"`
+	// lcm holds the 15 rows of the LCM, indexed by LIC.
+	Lcm           []*LcmRow   `protobuf:"bytes,3,rep,name=lcm,proto3" json:"lcm,omitempty"`
+	Puv           []bool      `protobuf:"varint,4,rep,packed,name=puv,proto3" json:"puv,omitempty"`
+	Parameters    *Parameters `protobuf:"bytes,5,opt,name=parameters,proto3" json:"parameters,omitempty"`
+	RandomSeed    *int64      `protobuf:"varint,6,opt,name=random_seed,json=randomSeed,proto3,oneof" json:"random_seed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Input) Reset() {
+	*x = Input{}
+	mi := &file_decide_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Input) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Input) ProtoMessage() {}
+
+func (x *Input) ProtoReflect() protoreflect.Message {
+	mi := &file_decide_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Input.ProtoReflect.Descriptor instead.
+func (*Input) Descriptor() ([]byte, []int) {
+	return file_decide_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Input) GetNumPoints() int32 {
+	if x != nil {
+		return x.NumPoints
+	}
+	return 0
+}
+
+func (x *Input) GetPoints() []*Point {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+func (x *Input) GetLcm() []*LcmRow {
+	if x != nil {
+		return x.Lcm
+	}
+	return nil
+}
+
+func (x *Input) GetPuv() []bool {
+	if x != nil {
+		return x.Puv
+	}
+	return nil
+}
+
+func (x *Input) GetParameters() *Parameters {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
+}
+
+func (x *Input) GetRandomSeed() int64 {
+	if x != nil && x.RandomSeed != nil {
+		return *x.RandomSeed
+	}
+	return 0
+}
+
+// PumRow is the row of the PUM of a LIC: 15 values.
+type PumRow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []bool                 `protobuf:"varint,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PumRow) Reset() {
+	*x = PumRow{}
+	mi := &file_decide_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PumRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PumRow) ProtoMessage() {}
+
+func (x *PumRow) ProtoReflect() protoreflect.Message {
+	mi := &file_decide_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PumRow.ProtoReflect.Descriptor instead.
+func (*PumRow) Descriptor() ([]byte, []int) {
+	return file_decide_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PumRow) GetValues() []bool {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// Bounds is the bounding box of the points of an engine, edges included.
+type Bounds struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Min           *Point                 `protobuf:"bytes,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max           *Point                 `protobuf:"bytes,2,opt,name=max,proto3" json:"max,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Bounds) Reset() {
+	*x = Bounds{}
+	mi := &file_decide_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Bounds) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bounds) ProtoMessage() {}
+
+func (x *Bounds) ProtoReflect() protoreflect.Message {
+	mi := &file_decide_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bounds.ProtoReflect.Descriptor instead.
+func (*Bounds) Descriptor() ([]byte, []int) {
+	return file_decide_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Bounds) GetMin() *Point {
+	if x != nil {
+		return x.Min
+	}
+	return nil
+}
+
+func (x *Bounds) GetMax() *Point {
+	if x != nil {
+		return x.Max
+	}
+	return nil
+}
+
+// Provenance records the engine of a result, so that it can be replayed.
+type Provenance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Input         string                 `protobuf:"bytes,1,opt,name=input,proto3" json:"input,omitempty"`
+	InputSha256   string                 `protobuf:"bytes,2,opt,name=input_sha256,json=inputSha256,proto3" json:"input_sha256,omitempty"`
+	RandomSeed    *int64                 `protobuf:"varint,3,opt,name=random_seed,json=randomSeed,proto3,oneof" json:"random_seed,omitempty"`
+	EngineVersion string                 `protobuf:"bytes,4,opt,name=engine_version,json=engineVersion,proto3" json:"engine_version,omitempty"`
+	Mode          string                 `protobuf:"bytes,5,opt,name=mode,proto3" json:"mode,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// evaluation is empty for the float evaluation.
+	Evaluation string `protobuf:"bytes,7,opt,name=evaluation,proto3" json:"evaluation,omitempty"`
+	// max_points is 0 for the default maximum NUMPOINTS.
+	MaxPoints int64 `protobuf:"varint,8,opt,name=max_points,json=maxPoints,proto3" json:"max_points,omitempty"`
+	// bounds is unset when the points were not bounded.
+	Bounds        *Bounds `protobuf:"bytes,9,opt,name=bounds,proto3" json:"bounds,omitempty"`
+	Lazy          bool    `protobuf:"varint,10,opt,name=lazy,proto3" json:"lazy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Provenance) Reset() {
+	*x = Provenance{}
+	mi := &file_decide_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Provenance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Provenance) ProtoMessage() {}
+
+func (x *Provenance) ProtoReflect() protoreflect.Message {
+	mi := &file_decide_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Provenance.ProtoReflect.Descriptor instead.
+func (*Provenance) Descriptor() ([]byte, []int) {
+	return file_decide_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Provenance) GetInput() string {
+	if x != nil {
+		return x.Input
+	}
+	return ""
+}
+
+func (x *Provenance) GetInputSha256() string {
+	if x != nil {
+		return x.InputSha256
+	}
+	return ""
+}
+
+func (x *Provenance) GetRandomSeed() int64 {
+	if x != nil && x.RandomSeed != nil {
+		return *x.RandomSeed
+	}
+	return 0
+}
+
+func (x *Provenance) GetEngineVersion() string {
+	if x != nil {
+		return x.EngineVersion
+	}
+	return ""
+}
+
+func (x *Provenance) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *Provenance) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Provenance) GetEvaluation() string {
+	if x != nil {
+		return x.Evaluation
+	}
+	return ""
+}
+
+func (x *Provenance) GetMaxPoints() int64 {
+	if x != nil {
+		return x.MaxPoints
+	}
+	return 0
+}
+
+func (x *Provenance) GetBounds() *Bounds {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+func (x *Provenance) GetLazy() bool {
+	if x != nil {
+		return x.Lazy
+	}
+	return false
+}
+
+type Result struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// launch is YES or NO.
+	Launch     string      `protobuf:"bytes,1,opt,name=launch,proto3" json:"launch,omitempty"`
+	Cmv        []bool      `protobuf:"varint,2,rep,packed,name=cmv,proto3" json:"cmv,omitempty"`
+	Pum        []*PumRow   `protobuf:"bytes,3,rep,name=pum,proto3" json:"pum,omitempty"`
+	Fuv        []bool      `protobuf:"varint,4,rep,packed,name=fuv,proto3" json:"fuv,omitempty"`
+	Provenance *Provenance `protobuf:"bytes,5,opt,name=provenance,proto3" json:"provenance,omitempty"`
+	// not_evaluated lists the LICs skipped by a lazy evaluation, whose CMV
+	// entries are false without being evaluated.
+	NotEvaluated  []int32 `protobuf:"varint,6,rep,packed,name=not_evaluated,json=notEvaluated,proto3" json:"not_evaluated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Result) Reset() {
+	*x = Result{}
+	mi := &file_decide_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Result) ProtoMessage() {}
+
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_decide_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_decide_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Result) GetLaunch() string {
+	if x != nil {
+		return x.Launch
+	}
+	return ""
+}
+
+func (x *Result) GetCmv() []bool {
+	if x != nil {
+		return x.Cmv
+	}
+	return nil
+}
+
+func (x *Result) GetPum() []*PumRow {
+	if x != nil {
+		return x.Pum
+	}
+	return nil
+}
+
+func (x *Result) GetFuv() []bool {
+	if x != nil {
+		return x.Fuv
+	}
+	return nil
+}
+
+func (x *Result) GetProvenance() *Provenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+func (x *Result) GetNotEvaluated() []int32 {
+	if x != nil {
+		return x.NotEvaluated
+	}
+	return nil
+}
+
+type Error struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// field is the offending input field of a validation error.
+	Field         string `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_decide_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_decide_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_decide_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Error) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+type EvaluateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id is copied to the response to correlate streamed messages.
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Input         *Input `protobuf:"bytes,2,opt,name=input,proto3" json:"input,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluateRequest) Reset() {
+	*x = EvaluateRequest{}
+	mi := &file_decide_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateRequest) ProtoMessage() {}
+
+func (x *EvaluateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_decide_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateRequest.ProtoReflect.Descriptor instead.
+func (*EvaluateRequest) Descriptor() ([]byte, []int) {
+	return file_decide_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *EvaluateRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *EvaluateRequest) GetInput() *Input {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+type EvaluateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are valid to be assigned to Outcome:
+	//
+	//	*EvaluateResponse_Result
+	//	*EvaluateResponse_Error
+	Outcome       isEvaluateResponse_Outcome `protobuf_oneof:"outcome"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluateResponse) Reset() {
+	*x = EvaluateResponse{}
+	mi := &file_decide_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateResponse) ProtoMessage() {}
+
+func (x *EvaluateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_decide_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateResponse.ProtoReflect.Descriptor instead.
+func (*EvaluateResponse) Descriptor() ([]byte, []int) {
+	return file_decide_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *EvaluateResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *EvaluateResponse) GetOutcome() isEvaluateResponse_Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return nil
+}
+
+func (x *EvaluateResponse) GetResult() *Result {
+	if x != nil {
+		if x, ok := x.Outcome.(*EvaluateResponse_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+func (x *EvaluateResponse) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Outcome.(*EvaluateResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isEvaluateResponse_Outcome interface {
+	isEvaluateResponse_Outcome()
+}
+
+type EvaluateResponse_Result struct {
+	Result *Result `protobuf:"bytes,2,opt,name=result,proto3,oneof"`
+}
+
+type EvaluateResponse_Error struct {
+	Error *Error `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*EvaluateResponse_Result) isEvaluateResponse_Outcome() {}
+
+func (*EvaluateResponse_Error) isEvaluateResponse_Outcome() {}
+
+var File_decide_proto protoreflect.FileDescriptor
+
+const file_decide_proto_rawDesc = "" +
+	"\n" +
+	"\fdecide.proto\x12\tdecide.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"#\n" +
+	"\x05Point\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x01R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x01R\x01y\"\xb6\x03\n" +
+	"\n" +
+	"Parameters\x12\x18\n" +
+	"\aradius1\x18\x01 \x01(\x01R\aradius1\x12\x18\n" +
+	"\aradius2\x18\x02 \x01(\x01R\aradius2\x12\x18\n" +
+	"\alength1\x18\x03 \x01(\x01R\alength1\x12\x18\n" +
+	"\alength2\x18\x04 \x01(\x01R\alength2\x12\x12\n" +
+	"\x04dist\x18\x05 \x01(\x01R\x04dist\x12\x18\n" +
+	"\aepsilon\x18\x06 \x01(\x01R\aepsilon\x12\x14\n" +
+	"\x05quads\x18\a \x01(\x05R\x05quads\x12\x14\n" +
+	"\x05area1\x18\b \x01(\x01R\x05area1\x12\x14\n" +
+	"\x05area2\x18\t \x01(\x01R\x05area2\x12\x13\n" +
+	"\x05a_pts\x18\n" +
+	" \x01(\x05R\x04aPts\x12\x13\n" +
+	"\x05b_pts\x18\v \x01(\x05R\x04bPts\x12\x13\n" +
+	"\x05c_pts\x18\f \x01(\x05R\x04cPts\x12\x13\n" +
+	"\x05d_pts\x18\r \x01(\x05R\x04dPts\x12\x13\n" +
+	"\x05e_pts\x18\x0e \x01(\x05R\x04ePts\x12\x13\n" +
+	"\x05f_pts\x18\x0f \x01(\x05R\x04fPts\x12\x13\n" +
+	"\x05g_pts\x18\x10 \x01(\x05R\x04gPts\x12\x13\n" +
+	"\x05k_pts\x18\x11 \x01(\x05R\x04kPts\x12\x13\n" +
+	"\x05n_pts\x18\x12 \x01(\x05R\x04nPts\x12\x13\n" +
+	"\x05q_pts\x18\x13 \x01(\x05R\x04qPts\"8\n" +
+	"\x06LcmRow\x12.\n" +
+	"\bcommands\x18\x01 \x03(\x0e2\x12.decide.v1.CommandR\bcommands\"\xf4\x01\n" +
+	"\x05Input\x12\x1d\n" +
+	"\n" +
+	"num_points\x18\x01 \x01(\x05R\tnumPoints\x12(\n" +
+	"\x06points\x18\x02 \x03(\v2\x10.decide.v1.PointR\x06points\x12#\n" +
+	"\x03lcm\x18\x03 \x03(\v2\x11.decide.v1.LcmRowR\x03lcm\x12\x10\n" +
+	"\x03puv\x18\x04 \x03(\bR\x03puv\x125\n" +
+	"\n" +
+	"parameters\x18\x05 \x01(\v2\x15.decide.v1.ParametersR\n" +
+	"parameters\x12$\n" +
+	"\vrandom_seed\x18\x06 \x01(\x03H\x00R\n" +
+	"randomSeed\x88\x01\x01B\x0e\n" +
+	"\f_random_seed\" \n" +
+	"\x06PumRow\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\bR\x06values\"P\n" +
+	"\x06Bounds\x12\"\n" +
+	"\x03min\x18\x01 \x01(\v2\x10.decide.v1.PointR\x03min\x12\"\n" +
+	"\x03max\x18\x02 \x01(\v2\x10.decide.v1.PointR\x03max\"\xee\x02\n" +
+	"\n" +
+	"Provenance\x12\x14\n" +
+	"\x05input\x18\x01 \x01(\tR\x05input\x12!\n" +
+	"\finput_sha256\x18\x02 \x01(\tR\vinputSha256\x12$\n" +
+	"\vrandom_seed\x18\x03 \x01(\x03H\x00R\n" +
+	"randomSeed\x88\x01\x01\x12%\n" +
+	"\x0eengine_version\x18\x04 \x01(\tR\rengineVersion\x12\x12\n" +
+	"\x04mode\x18\x05 \x01(\tR\x04mode\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1e\n" +
+	"\n" +
+	"evaluation\x18\a \x01(\tR\n" +
+	"evaluation\x12\x1d\n" +
+	"\n" +
+	"max_points\x18\b \x01(\x03R\tmaxPoints\x12)\n" +
+	"\x06bounds\x18\t \x01(\v2\x11.decide.v1.BoundsR\x06bounds\x12\x12\n" +
+	"\x04lazy\x18\n" +
+	" \x01(\bR\x04lazyB\x0e\n" +
+	"\f_random_seed\"\xc5\x01\n" +
+	"\x06Result\x12\x16\n" +
+	"\x06launch\x18\x01 \x01(\tR\x06launch\x12\x10\n" +
+	"\x03cmv\x18\x02 \x03(\bR\x03cmv\x12#\n" +
+	"\x03pum\x18\x03 \x03(\v2\x11.decide.v1.PumRowR\x03pum\x12\x10\n" +
+	"\x03fuv\x18\x04 \x03(\bR\x03fuv\x125\n" +
+	"\n" +
+	"provenance\x18\x05 \x01(\v2\x15.decide.v1.ProvenanceR\n" +
+	"provenance\x12#\n" +
+	"\rnot_evaluated\x18\x06 \x03(\x05R\fnotEvaluated\"7\n" +
+	"\x05Error\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x14\n" +
+	"\x05field\x18\x02 \x01(\tR\x05field\"I\n" +
+	"\x0fEvaluateRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12&\n" +
+	"\x05input\x18\x02 \x01(\v2\x10.decide.v1.InputR\x05input\"\x84\x01\n" +
+	"\x10EvaluateResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12+\n" +
+	"\x06result\x18\x02 \x01(\v2\x11.decide.v1.ResultH\x00R\x06result\x12(\n" +
+	"\x05error\x18\x03 \x01(\v2\x10.decide.v1.ErrorH\x00R\x05errorB\t\n" +
+	"\aoutcome*B\n" +
+	"\aCommand\x12\x17\n" +
+	"\x13COMMAND_UNSPECIFIED\x10\x00\x12\b\n" +
+	"\x04ANDD\x10\x01\x12\a\n" +
+	"\x03ORR\x10\x02\x12\v\n" +
+	"\aNOTUSED\x10\x032\xa5\x01\n" +
+	"\x0fDecisionService\x12C\n" +
+	"\bEvaluate\x12\x1a.decide.v1.EvaluateRequest\x1a\x1b.decide.v1.EvaluateResponse\x12M\n" +
+	"\x0eEvaluateStream\x12\x1a.decide.v1.EvaluateRequest\x1a\x1b.decide.v1.EvaluateResponse(\x010\x01B(Z&github.com/tdurieux/go-decide/decidepbb\x06proto3"
+
+var (
+	file_decide_proto_rawDescOnce sync.Once
+	file_decide_proto_rawDescData []byte
+)
+
+func file_decide_proto_rawDescGZIP() []byte {
+	file_decide_proto_rawDescOnce.Do(func() {
+		file_decide_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_decide_proto_rawDesc), len(file_decide_proto_rawDesc)))
+	})
+	return file_decide_proto_rawDescData
+}
+
+var file_decide_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_decide_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_decide_proto_goTypes = []any{
+	(Command)(0),                  // 0: decide.v1.Command
+	(*Point)(nil),                 // 1: decide.v1.Point
+	(*Parameters)(nil),            // 2: decide.v1.Parameters
+	(*LcmRow)(nil),                // 3: decide.v1.LcmRow
+	(*Input)(nil),                 // 4: decide.v1.Input
+	(*PumRow)(nil),                // 5: decide.v1.PumRow
+	(*Bounds)(nil),                // 6: decide.v1.Bounds
+	(*Provenance)(nil),            // 7: decide.v1.Provenance
+	(*Result)(nil),                // 8: decide.v1.Result
+	(*Error)(nil),                 // 9: decide.v1.Error
+	(*EvaluateRequest)(nil),       // 10: decide.v1.EvaluateRequest
+	(*EvaluateResponse)(nil),      // 11: decide.v1.EvaluateResponse
+	(*timestamppb.Timestamp)(nil), // 12: google.protobuf.Timestamp
+}
+var file_decide_proto_depIdxs = []int32{
+	0,  // 0: decide.v1.LcmRow.commands:type_name -> decide.v1.Command
+	1,  // 1: decide.v1.Input.points:type_name -> decide.v1.Point
+	3,  // 2: decide.v1.Input.lcm:type_name -> decide.v1.LcmRow
+	2,  // 3: decide.v1.Input.parameters:type_name -> decide.v1.Parameters
+	1,  // 4: decide.v1.Bounds.min:type_name -> decide.v1.Point
+	1,  // 5: decide.v1.Bounds.max:type_name -> decide.v1.Point
+	12, // 6: decide.v1.Provenance.timestamp:type_name -> google.protobuf.Timestamp
+	6,  // 7: decide.v1.Provenance.bounds:type_name -> decide.v1.Bounds
+	5,  // 8: decide.v1.Result.pum:type_name -> decide.v1.PumRow
+	7,  // 9: decide.v1.Result.provenance:type_name -> decide.v1.Provenance
+	4,  // 10: decide.v1.EvaluateRequest.input:type_name -> decide.v1.Input
+	8,  // 11: decide.v1.EvaluateResponse.result:type_name -> decide.v1.Result
+	9,  // 12: decide.v1.EvaluateResponse.error:type_name -> decide.v1.Error
+	10, // 13: decide.v1.DecisionService.Evaluate:input_type -> decide.v1.EvaluateRequest
+	10, // 14: decide.v1.DecisionService.EvaluateStream:input_type -> decide.v1.EvaluateRequest
+	11, // 15: decide.v1.DecisionService.Evaluate:output_type -> decide.v1.EvaluateResponse
+	11, // 16: decide.v1.DecisionService.EvaluateStream:output_type -> decide.v1.EvaluateResponse
+	15, // [15:17] is the sub-list for method output_type
+	13, // [13:15] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_decide_proto_init() }
+func file_decide_proto_init() {
+	if File_decide_proto != nil {
+		return
+	}
+	file_decide_proto_msgTypes[3].OneofWrappers = []any{}
+	file_decide_proto_msgTypes[6].OneofWrappers = []any{}
+	file_decide_proto_msgTypes[10].OneofWrappers = []any{
+		(*EvaluateResponse_Result)(nil),
+		(*EvaluateResponse_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_decide_proto_rawDesc), len(file_decide_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_decide_proto_goTypes,
+		DependencyIndexes: file_decide_proto_depIdxs,
+		EnumInfos:         file_decide_proto_enumTypes,
+		MessageInfos:      file_decide_proto_msgTypes,
+	}.Build()
+	File_decide_proto = out.File
+	file_decide_proto_goTypes = nil
+	file_decide_proto_depIdxs = nil
+}
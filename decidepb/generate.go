@@ -0,0 +1,5 @@
+// Package decidepb holds the protobuf messages and the gRPC service
+// generated from proto/decide.proto.
+package decidepb
+
+//go:generate protoc -I ../proto --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative decide.proto
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"github.com/tdurieux/go-decide/decide"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+func diffCmd(args []string) int {
+	flags := newFlagSet("diff", "result1 result2 | -engine path input",
+		"Compares two result files, or the result of this engine with the\n" +
+		"result of another decide binary on the same input.")
+	engine := flags.String("engine", "", "the path to another decide binary to compare with")
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+
+	var a, b decide.Decide
+	var err error
+	switch {
+	case *engine != "" && flags.NArg() == 1:
+		a, b, err = diffEngines(*engine, flags.Arg(0))
+	case *engine == "" && flags.NArg() == 2:
+		if a, err = getDecision(flags.Arg(0)); err == nil {
+			b, err = getDecision(flags.Arg(1))
+		}
+	default:
+		flags.Usage()
+		return exitInput
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitCode(err)
+	}
+
+	diffs := decide.Compare(a, b)
+	for _, d := range diffs {
+		fmt.Println(d)
+	}
+	if len(diffs) != 0 {
+		return exitNo
+	}
+	return exitOK
+}
+
+// diffEngines evaluates inputPath with this engine and with the decide
+// binary at enginePath, fed through its stdin streaming mode.
+func diffEngines(enginePath string, inputPath string) (decide.Decide, decide.Decide, error) {
+	var local, other decide.Decide
+	input, err := getInput(inputPath)
+	if err != nil {
+		return local, other, err
+	}
+	if err := local.Decide(input); err != nil {
+		return local, other, err
+	}
+
+	content, err := json.Marshal(input)
+	if err != nil {
+		return local, other, err
+	}
+	cmd := exec.Command(enginePath, "-")
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil && len(out) == 0 {
+		return local, other, err
+	}
+	result := streamResult{Decide: &other}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return local, other, err
+	}
+	if result.Error != "" {
+		return local, other, inputError{fmt.Errorf("%s: %s", enginePath, result.Error)}
+	}
+	return local, other, nil
+}
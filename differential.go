@@ -0,0 +1,78 @@
+package main
+
+import (
+	"github.com/tdurieux/go-decide/decide"
+	"github.com/tdurieux/go-decide/differential"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func differentialCmd(args []string) int {
+	flags := newFlagSet("differential", "[input file or directory]",
+		"Decides the inputs, and random inputs with their grid and boundary\n" +
+		"variants, with each evaluation of the engine and reports the LICs\n" +
+		"whose CMV depends on the evaluation, near a threshold or not.")
+	n := flags.Int("generate", 100, "the number of random inputs to add")
+	seed := flags.Int64("seed", 0, "the seed of the first random input")
+	list := flags.String("evaluations", "float,tolerant,exact,strict", "the evaluations to compare, the first one is the reference")
+	limit := flags.Int("limit", 20, "the maximum number of disagreements listed by class, all when 0")
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return exitInput
+	}
+	var evaluations []decide.Evaluation
+	for _, name := range strings.Split(*list, ",") {
+		evaluation, err := parseEvaluation(strings.TrimSpace(name))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "invalid -evaluations:", err)
+			return exitInput
+		}
+		evaluations = append(evaluations, evaluation)
+	}
+
+	var cases []differential.Case
+	if flags.NArg() == 1 {
+		paths, err := inputFiles(flags.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitInput
+		}
+		for _, path := range paths {
+			input, err := getInput(path)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, path, err)
+				return exitInput
+			}
+			cases = append(cases, differential.Case{Source: path, Input: input})
+		}
+	}
+	cases = append(cases, differential.Generated(*seed, *n)...)
+
+	report, err := differential.Run(context.Background(), decide.Engine{}, evaluations, cases)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInternal
+	}
+	if *asJSON {
+		content, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitInternal
+		}
+		fmt.Println(string(content))
+	} else if err := report.WriteTriage(os.Stdout, *limit); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInternal
+	}
+	if !report.Consistent() {
+		return exitNo
+	}
+	return exitOK
+}
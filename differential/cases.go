@@ -0,0 +1,112 @@
+package differential
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/tdurieux/go-decide/decide"
+)
+
+// Generated returns n random inputs drawn from the seeds seed to
+// seed+n-1, each followed by two variants where the evaluations are more
+// likely to disagree: its grid variant and its boundary variant.
+func Generated(seed int64, n int) []Case {
+	cases := make([]Case, 0, 3 * n)
+	for s := seed; s < seed + int64(n); s++ {
+		input := decide.Generate(s)
+		cases = append(cases,
+			Case{fmt.Sprintf("generated:%d", s), input},
+			Case{fmt.Sprintf("grid:%d", s), Grid(s)},
+			Case{fmt.Sprintf("boundary:%d", s), Boundary(input)},
+		)
+	}
+	return cases
+}
+
+// Grid returns the input generated from seed with few points whose
+// coordinates and thresholds are small integers: coincident and
+// collinear points, right angles and quantities equal to their
+// thresholds are frequent.
+func Grid(seed int64) decide.INPUT {
+	config := decide.DefaultGeneratorConfig()
+	config.NumPoints = decide.Range{Min: 5, Max: 20}
+	config.Coordinates = decide.Range{Min: -3, Max: 3}
+	for _, name := range []string{"LENGTH1", "LENGTH2", "RADIUS1", "RADIUS2", "DIST", "AREA1", "AREA2"} {
+		config.Parameters[name] = decide.Range{Min: 0, Max: 4}
+	}
+	input, err := decide.GenerateWith(seed, config)
+	if err != nil {
+		panic(err)
+	}
+	for i, p := range input.Points {
+		input.Points[i] = [2]float64{math.Round(p[0]), math.Round(p[1])}
+	}
+	p := &input.Parameters
+	for _, v := range []*float64{&p.LENGTH1, &p.LENGTH2, &p.RADIUS1, &p.RADIUS2, &p.DIST, &p.AREA1, &p.AREA2} {
+		*v = math.Round(*v)
+	}
+	return input
+}
+
+// Boundary returns a copy of input whose real thresholds are set to the
+// quantity they are compared with on the first set of points of their
+// LIC, so that each LIC is evaluated exactly at its threshold at least
+// once.
+func Boundary(input decide.INPUT) decide.INPUT {
+	points := append([][2]float64(nil), input.Points...)
+	input.Points = points
+	at := func(i int) [2]float64 {
+		if i >= len(points) {
+			i = len(points) - 1
+		}
+		return points[i]
+	}
+	p := &input.Parameters
+	p.LENGTH1 = distance(at(0), at(1))
+	p.LENGTH2 = distance(at(0), at(p.K_PTS + 1))
+	p.RADIUS1 = halfDiameter(at(0), at(p.A_PTS + 1), at(p.A_PTS + p.B_PTS + 2))
+	p.RADIUS2 = p.RADIUS1
+	p.AREA1 = area(at(0), at(1), at(2))
+	p.AREA2 = area(at(0), at(p.E_PTS + 1), at(p.E_PTS + p.F_PTS + 2))
+	if p.N_PTS >= 3 {
+		p.DIST = lineDistance(at(1), at(0), at(p.N_PTS - 1))
+	}
+	if angle, ok := angle(at(0), at(1), at(2)); ok && angle > 0 {
+		p.EPSILON = math.Pi - angle
+	}
+	return input
+}
+
+func distance(p [2]float64, q [2]float64) float64 {
+	return math.Hypot(p[0] - q[0], p[1] - q[1])
+}
+
+// halfDiameter returns half the largest distance between the points, the
+// radius of the smallest circle that contains them unless they form an
+// acute triangle.
+func halfDiameter(p1 [2]float64, p2 [2]float64, p3 [2]float64) float64 {
+	return math.Max(distance(p1, p2), math.Max(distance(p2, p3), distance(p1, p3))) / 2
+}
+
+func area(p1 [2]float64, p2 [2]float64, p3 [2]float64) float64 {
+	return math.Abs((p2[0] - p1[0]) * (p3[1] - p1[1]) - (p3[0] - p1[0]) * (p2[1] - p1[1])) / 2
+}
+
+func lineDistance(p [2]float64, p1 [2]float64, p2 [2]float64) float64 {
+	d := distance(p1, p2)
+	if d == 0 {
+		return distance(p, p1)
+	}
+	return math.Abs((p2[0] - p1[0]) * (p1[1] - p[1]) - (p1[0] - p[0]) * (p2[1] - p1[1])) / d
+}
+
+// angle returns the unsigned angle at vertex b, and false when it is
+// undefined.
+func angle(a [2]float64, b [2]float64, c [2]float64) (float64, bool) {
+	if a == b || c == b {
+		return 0, false
+	}
+	u := [2]float64{a[0] - b[0], a[1] - b[1]}
+	v := [2]float64{c[0] - b[0], c[1] - b[1]}
+	return math.Abs(math.Atan2(u[0] * v[1] - u[1] * v[0], u[0] * v[0] + u[1] * v[1])), true
+}
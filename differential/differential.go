@@ -0,0 +1,226 @@
+// Package differential evaluates inputs with every evaluation of the
+// engine and triages the LICs on which they disagree.
+package differential
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/tdurieux/go-decide/decide"
+)
+
+// NearMargin is the margin under which a disagreement is attributed to
+// the rounding of a quantity close to its threshold, see decide.Margins.
+const NearMargin = 1e-6
+
+// Classes of disagreements.
+const (
+	// ClassThreshold is a disagreement on a quantity within NearMargin of
+	// its threshold.
+	ClassThreshold = "THRESHOLD"
+	// ClassSemantics is a disagreement far from any threshold: the
+	// evaluations read the specification differently.
+	ClassSemantics = "SEMANTICS"
+)
+
+// Case is an input to evaluate.
+type Case struct {
+	// Source locates the input, e.g. a file or a generator seed.
+	Source string
+	Input  decide.INPUT
+}
+
+// Disagreement is a LIC of an input whose CMV entry depends on the
+// evaluation.
+type Disagreement struct {
+	Source string                   `json:"SOURCE"`
+	LIC    int                      `json:"LIC"`
+	Values map[decide.Evaluation]bool `json:"VALUES"`
+	// Margin is the margin of the LIC, omitted when it compares no
+	// quantity.
+	Margin *float64 `json:"MARGIN,omitempty"`
+	Class  string   `json:"CLASS"`
+}
+
+// Summary counts the disagreements on a LIC by class.
+type Summary struct {
+	Threshold int `json:"THRESHOLD"`
+	Semantics int `json:"SEMANTICS"`
+}
+
+// Report summarizes a differential run.
+type Report struct {
+	// Evaluations lists the compared evaluations, the first one is the
+	// reference.
+	Evaluations []decide.Evaluation `json:"EVALUATIONS"`
+	Inputs      int                 `json:"INPUTS"`
+	// Rejected counts the inputs that are not valid.
+	Rejected      int            `json:"REJECTED"`
+	Disagreements []Disagreement `json:"DISAGREEMENTS"`
+	// Inconsistent counts the inputs with at least one disagreement.
+	Inconsistent int `json:"INCONSISTENT"`
+	// Launch counts the inputs whose LAUNCH depends on the evaluation.
+	Launch int                     `json:"LAUNCH"`
+	LICs   [decide.NB_LIC]Summary  `json:"LICS"`
+	// Against counts, for each evaluation, the disagreements where it
+	// differs from the reference.
+	Against map[decide.Evaluation]int `json:"AGAINST"`
+}
+
+// Consistent reports whether every evaluation gave the same CMV on every
+// input.
+func (r Report) Consistent() bool {
+	return len(r.Disagreements) == 0
+}
+
+// Run decides every case with a copy of engine for each evaluation and
+// compares their CMV. An error is returned for an unknown evaluation.
+func Run(ctx context.Context, engine decide.Engine, evaluations []decide.Evaluation, cases []Case) (Report, error) {
+	report := Report{
+		Evaluations:   evaluations,
+		Disagreements: []Disagreement{},
+		Against:       make(map[decide.Evaluation]int, len(evaluations)),
+	}
+	if len(evaluations) == 0 {
+		return report, fmt.Errorf("no evaluation to compare")
+	}
+	engines := make([]decide.Engine, len(evaluations))
+	for i, evaluation := range evaluations {
+		engines[i] = engine
+		engines[i].Evaluation = evaluation
+	}
+	for _, c := range cases {
+		report.Inputs++
+		margins, err := decide.Margins(c.Input)
+		if err != nil {
+			report.Rejected++
+			continue
+		}
+		results := make([]decide.Decide, len(engines))
+		for i := range engines {
+			results[i], err = engines[i].Decide(ctx, c.Input)
+			if err != nil {
+				break
+			}
+		}
+		if err != nil {
+			var validation *decide.ValidationError
+			if errors.As(err, &validation) {
+				report.Rejected++
+				continue
+			}
+			return report, fmt.Errorf("%s: %v", c.Source, err)
+		}
+		if report.compare(c.Source, results, margins) {
+			report.Inconsistent++
+		}
+		for _, result := range results[1:] {
+			if result.Launch != results[0].Launch {
+				report.Launch++
+				break
+			}
+		}
+	}
+	return report, nil
+}
+
+// compare records the disagreements between results and reports whether
+// there is any.
+func (r *Report) compare(source string, results []decide.Decide, margins [decide.NB_LIC]float64) bool {
+	found := false
+	for lic := 0; lic < decide.NB_LIC; lic++ {
+		reference := results[0].CMV[lic]
+		agree := true
+		for _, result := range results[1:] {
+			if result.CMV[lic] != reference {
+				agree = false
+			}
+		}
+		if agree {
+			continue
+		}
+		found = true
+		d := Disagreement{Source: source, LIC: lic, Values: make(map[decide.Evaluation]bool, len(results)), Class: ClassSemantics}
+		for i, result := range results {
+			d.Values[r.Evaluations[i]] = result.CMV[lic]
+			if result.CMV[lic] != reference {
+				r.Against[r.Evaluations[i]]++
+			}
+		}
+		if !math.IsInf(margins[lic], 1) {
+			margin := margins[lic]
+			d.Margin = &margin
+		}
+		if margins[lic] <= NearMargin {
+			d.Class = ClassThreshold
+			r.LICs[lic].Threshold++
+		} else {
+			r.LICs[lic].Semantics++
+		}
+		r.Disagreements = append(r.Disagreements, d)
+	}
+	return found
+}
+
+// WriteTriage writes a summary of r to w followed by its disagreements,
+// the semantic ones first, at most limit of each class when limit is
+// positive.
+func (r Report) WriteTriage(w io.Writer, limit int) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d inputs, %d rejected, %d with disagreements, %d with a LAUNCH disagreement\n",
+		r.Inputs, r.Rejected, r.Inconsistent, r.Launch)
+	if len(r.Disagreements) == 0 {
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+	for _, evaluation := range r.Evaluations[1:] {
+		fmt.Fprintf(&b, "%s differs from %s on %d LICs\n", evaluation, r.Evaluations[0], r.Against[evaluation])
+	}
+	fmt.Fprintf(&b, "\nLIC  %-9s  %-9s\n", ClassThreshold, ClassSemantics)
+	for lic, summary := range r.LICs {
+		if summary.Threshold + summary.Semantics != 0 {
+			fmt.Fprintf(&b, "%-3d  %-9d  %-9d\n", lic, summary.Threshold, summary.Semantics)
+		}
+	}
+
+	disagreements := append([]Disagreement(nil), r.Disagreements...)
+	sort.SliceStable(disagreements, func(i, j int) bool {
+		if disagreements[i].Class != disagreements[j].Class {
+			return disagreements[i].Class == ClassSemantics
+		}
+		return disagreements[i].LIC < disagreements[j].LIC
+	})
+	titles := map[string]string{
+		ClassSemantics: "far from the thresholds, a different reading of the specification",
+		ClassThreshold: "near a threshold, a matter of precision",
+	}
+	shown := 0
+	for i, d := range disagreements {
+		if i == 0 || d.Class != disagreements[i - 1].Class {
+			fmt.Fprintf(&b, "\n%s: %s\n", d.Class, titles[d.Class])
+			shown = 0
+		}
+		shown++
+		if limit > 0 && shown > limit {
+			if shown == limit + 1 {
+				b.WriteString("\t...\n")
+			}
+			continue
+		}
+		fmt.Fprintf(&b, "\t%s LIC %d:", d.Source, d.LIC)
+		for _, evaluation := range r.Evaluations {
+			fmt.Fprintf(&b, " %s=%v", evaluation, d.Values[evaluation])
+		}
+		if d.Margin != nil {
+			fmt.Fprintf(&b, " margin=%.3g", *d.Margin)
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,109 @@
+package differential
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tdurieux/go-decide/decide"
+)
+
+// input returns a valid input of points with the parameters params.
+func input(points [][2]float64, params decide.Parameters) decide.INPUT {
+	in := decide.Generate(1)
+	in.NumPoints = len(points)
+	in.Points = points
+	params.Q_PTS, params.QUADS, params.N_PTS, params.K_PTS, params.G_PTS = 2, 1, 3, 1, 1
+	params.A_PTS, params.B_PTS, params.C_PTS, params.D_PTS, params.E_PTS, params.F_PTS = 1, 1, 1, 1, 1, 1
+	in.Parameters = params
+	return in
+}
+
+func TestRun(t *testing.T) {
+	cases := []Case{
+		// The float square root of 13 is below the distance between the
+		// points: only the exact evaluation finds it greater than LENGTH1.
+		{"sqrt", input([][2]float64{{0, 0}, {2, 3}, {2, 3}}, decide.Parameters{LENGTH1: math.Sqrt(13), LENGTH2: 100, RADIUS1: 100, AREA1: 100, AREA2: 100, DIST: 100, EPSILON: 3})},
+		// A nearly straight turn meets LIC 2 with a signed angle only.
+		{"turn", input([][2]float64{{0, 0}, {1, 0}, {2, 0.001}}, decide.Parameters{LENGTH1: 100, LENGTH2: 100, RADIUS1: 100, AREA1: 100, AREA2: 100, DIST: 100, EPSILON: 0.1})},
+		{"invalid", decide.INPUT{NumPoints: 1}},
+	}
+	report, err := Run(context.Background(), decide.Engine{}, decide.Evaluations, cases)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if report.Inputs != 3 || report.Rejected != 1 || report.Consistent() {
+		t.Error("Expected disagreements on 2 valid inputs out of 3, got", report)
+		return
+	}
+	var sqrt, turn *Disagreement
+	for i, d := range report.Disagreements {
+		if d.Source == "sqrt" && d.LIC == 0 {
+			sqrt = &report.Disagreements[i]
+		}
+		if d.Source == "turn" && d.LIC == 2 {
+			turn = &report.Disagreements[i]
+		}
+	}
+	if sqrt == nil || sqrt.Class != ClassThreshold || sqrt.Values[decide.EvaluationFloat] || !sqrt.Values[decide.EvaluationExact] {
+		t.Error("Expected a threshold disagreement of the exact evaluation on LIC 0, got", sqrt)
+		return
+	}
+	if turn == nil || turn.Class != ClassSemantics || !turn.Values[decide.EvaluationFloat] || turn.Values[decide.EvaluationStrict] {
+		t.Error("Expected a semantic disagreement of the strict evaluation on LIC 2, got", turn)
+		return
+	}
+	if report.LICs[0].Threshold != 1 || report.LICs[2].Semantics != 1 || report.Against[decide.EvaluationStrict] == 0 {
+		t.Error("Expected the disagreements to be counted, got", report.LICs, report.Against)
+		return
+	}
+
+	var b strings.Builder
+	if err := report.WriteTriage(&b, 10); err != nil {
+		t.Error(err)
+		return
+	}
+	triage := b.String()
+	if strings.Index(triage, ClassSemantics) > strings.Index(triage, ClassThreshold + ":") || !strings.Contains(triage, "turn LIC 2:") {
+		t.Error("Expected the semantic disagreements to be listed first, got", triage)
+		return
+	}
+	if _, err := json.Marshal(report); err != nil {
+		t.Error("Expected the report to encode", err)
+		return
+	}
+}
+
+func TestRunSingleEvaluation(t *testing.T) {
+	report, err := Run(context.Background(), decide.Engine{}, []decide.Evaluation{decide.EvaluationFloat}, Generated(0, 20))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !report.Consistent() || report.Inputs != 60 || report.Rejected != 0 {
+		t.Error("Expected an evaluation to agree with itself on 60 valid inputs, got", report)
+		return
+	}
+	if _, err := Run(context.Background(), decide.Engine{}, []decide.Evaluation{"approximate"}, Generated(0, 1)); err == nil {
+		t.Error("Expected an unknown evaluation to be rejected")
+		return
+	}
+}
+
+func TestBoundary(t *testing.T) {
+	for seed := int64(0); seed < 50; seed++ {
+		in := Boundary(decide.Generate(seed))
+		margins, err := decide.Margins(in)
+		if err != nil {
+			t.Error("Expected the boundary variant of seed", seed, "to be valid, got", err)
+			return
+		}
+		if margins[0] > 1e-12 {
+			t.Error("Expected LIC 0 to be evaluated at its threshold for seed", seed, "got a margin of", margins[0])
+			return
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"github.com/tdurieux/go-decide/decide"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+func explainCmd(args []string) int {
+	flags := newFlagSet("explain", "input",
+		"Evaluates an input and prints the causal chain from the CMV to the\n" +
+		"launch decision.")
+	asJSON := flags.Bool("json", false, "print the explanation as json")
+	policy := exitPolicyFlag(flags)
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitInput
+	}
+
+	input, err := getInput(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "unable to get the input file", err.Error())
+		return exitCode(err)
+	}
+	decision := decide.Decide{}
+	if err := decision.Decide(input); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitCode(err)
+	}
+	explanation := decision.Explain()
+	if *asJSON {
+		out, err := json.MarshalIndent(explanation, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitInternal
+		}
+		fmt.Println(string(out))
+	} else {
+		fmt.Println(explanation)
+	}
+	return policy.code(decision.Launch)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"github.com/tdurieux/go-decide/decide"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// rangeValue parses a decide.Range written min:max.
+type rangeValue struct {
+	r *decide.Range
+}
+
+func (v rangeValue) String() string {
+	if v.r == nil {
+		return ""
+	}
+	return fmt.Sprintf("%g:%g", v.r.Min, v.r.Max)
+}
+
+func (v rangeValue) Set(value string) error {
+	r, err := parseRange(value)
+	if err != nil {
+		return err
+	}
+	*v.r = r
+	return nil
+}
+
+func parseRange(value string) (decide.Range, error) {
+	var r decide.Range
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 {
+		return r, fmt.Errorf("expected min:max, got %q", value)
+	}
+	var err error
+	if r.Min, err = strconv.ParseFloat(parts[0], 64); err != nil {
+		return r, err
+	}
+	if r.Max, err = strconv.ParseFloat(parts[1], 64); err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+// paramsValue parses NAME=min:max into the parameter ranges of a config.
+type paramsValue struct {
+	params map[string]decide.Range
+}
+
+func (v paramsValue) String() string {
+	return ""
+}
+
+func (v paramsValue) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("expected NAME=min:max, got %q", value)
+	}
+	if _, ok := v.params[parts[0]]; !ok {
+		return fmt.Errorf("unknown parameter %q", parts[0])
+	}
+	r, err := parseRange(parts[1])
+	if err != nil {
+		return err
+	}
+	v.params[parts[0]] = r
+	return nil
+}
+
+func generateCmd(args []string) int {
+	flags := newFlagSet("generate", "",
+		"Synthesizes random inputs that respect the specification. The same\n" +
+		"seed and ranges always produce the same inputs, and each input\n" +
+		"records its seed in random_seed. The seeds of the input/ corpus come\n" +
+		"from another generator and do not reproduce its inputs.")
+	config := decide.DefaultGeneratorConfig()
+	seed := flags.Int64("seed", 1, "the seed of the first input, incremented for each next input")
+	count := flags.Int("count", 1, "the number of inputs to generate")
+	outputPath := flags.String("output", "", "the directory where the inputs are written, stdout if empty")
+	flags.Var(rangeValue{&config.NumPoints}, "numpoints", "the range of NUMPOINTS, min:max")
+	flags.IntVar(&config.MaxPoints, "max-points", decide.DefaultMaxPoints, "the maximum NUMPOINTS allowed in the range")
+	flags.Var(rangeValue{&config.Coordinates}, "coordinates", "the range of the coordinates, min:max")
+	flags.Var(paramsValue{config.Parameters}, "param", "the range of a parameter, NAME=min:max (repeatable)")
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+	if flags.NArg() != 0 || *count < 1 {
+		flags.Usage()
+		return exitInput
+	}
+
+	if *outputPath != "" {
+		if err := os.MkdirAll(*outputPath, 0700); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitInternal
+		}
+	}
+	for i := 0; i < *count; i++ {
+		input, err := decide.GenerateWith(*seed + int64(i), config)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitInput
+		}
+		content, err := json.MarshalIndent(input, "", " ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitInternal
+		}
+		if *outputPath == "" {
+			fmt.Println(string(content))
+			continue
+		}
+		outputFile := path.Join(*outputPath, fmt.Sprintf("input%d.json", i + 1))
+		if err := ioutil.WriteFile(outputFile, content, 0644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitInternal
+		}
+	}
+	return exitOK
+}
@@ -9,85 +9,372 @@ import (
 	"io/ioutil"
 	"strings"
 	"path"
+	"strconv"
+	"time"
+	"log/slog"
+	"errors"
+	"math"
 )
 
+const usage = `decide evaluates the launch interceptor conditions of the DECIDE specification.
+
+Usage:
+
+	decide <command> [flags] [arguments]
+
+Commands:
+
+	run       evaluate inputs and print the launch decision (default)
+	validate  check the structure and the parameter constraints of inputs
+	explain   print the causal chain of a decision
+	diff      compare two result files or two engine versions
+	generate  synthesize random valid inputs
+	serve     serve the engine over HTTP
+	keygen    create a key pair to sign decisions
+	sign      sign result files
+	verify    verify the signature of result files
+	replay    re-run recorded decisions and report those that changed
+	track     evaluate a stream of radar points over a sliding window
+	bench     benchmark the rules against a baseline
+	verify-log verify the integrity of an audit log
+	differential compare the evaluations of the engine and triage their disagreements
+	mutate    report the mutants of the rules that the tests do not detect
+	render    draw the points of an input and the witnesses of its LICs as SVG
+	report    write self-contained HTML reports of decisions
+	tui       explore and edit a decision interactively in the terminal
+
+Run "decide <command> -h" for the flags of a command.
+
+Exit codes:
+
+	0  every decision is YES (validate: every input is valid, diff: no
+	   difference, verify and verify-log: the signatures or the log are valid,
+	   replay: every decision is reproduced, bench: no regression,
+	   differential: the evaluations agree, mutate: every mutant is detected)
+	1  at least one decision is NO (diff: the results differ, verify and
+	   verify-log: a result or the log was tampered with, replay: a decision
+	   changed, bench: a benchmark regressed, differential: the evaluations
+	   disagree, mutate: a mutant survived)
+	2  unreadable, undecodable or invalid input, or invalid usage
+	3  internal error
+
+run and explain accept -exit-on to choose which decisions give a non-zero
+exit code: "no" (default), "yes" or "error" (decisions never do).
+`
+
+// command is a subcommand of the CLI. run parses args and returns the
+// process exit code.
+type command struct {
+	name string
+	run  func(args []string) int
+}
+
+var commands = []command{
+	{"run", runCmd},
+	{"validate", validateCmd},
+	{"explain", explainCmd},
+	{"diff", diffCmd},
+	{"generate", generateCmd},
+	{"serve", serveCmd},
+	{"keygen", keygenCmd},
+	{"sign", signCmd},
+	{"verify", verifyCmd},
+	{"replay", replayCmd},
+	{"track", trackCmd},
+	{"bench", benchCmd},
+	{"verify-log", verifyLogCmd},
+	{"differential", differentialCmd},
+	{"mutate", mutateCmd},
+	{"render", renderCmd},
+	{"report", reportCmd},
+	{"tui", tuiCmd},
+}
+
+// Exit codes of the process. When several inputs are processed, the
+// highest code wins.
+const (
+	exitOK       = 0
+	exitNo       = 1
+	exitInput    = 2
+	exitInternal = 3
+)
+
+// inputError marks an error caused by the input rather than by decide.
+type inputError struct {
+	error
+}
+
+// exitCode maps an error to the exit code of the process.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	var validation *decide.ValidationError
+	if errors.As(err, &validation) {
+		return exitInput
+	}
+	if _, ok := err.(inputError); ok {
+		return exitInput
+	}
+	return exitInternal
+}
+
+// exitPolicy decides which launch decisions give a non-zero exit code.
+type exitPolicy string
+
+const (
+	exitOnNo    exitPolicy = "no"
+	exitOnYes   exitPolicy = "yes"
+	exitOnError exitPolicy = "error"
+)
+
+func (p *exitPolicy) String() string {
+	return string(*p)
+}
+
+func (p *exitPolicy) Set(value string) error {
+	switch exitPolicy(value) {
+	case exitOnNo, exitOnYes, exitOnError:
+		*p = exitPolicy(value)
+		return nil
+	}
+	return fmt.Errorf("must be one of no, yes or error")
+}
+
+// code returns the exit code of a launch decision under the policy.
+func (p exitPolicy) code(launch string) int {
+	if p == exitOnNo && launch == "NO" || p == exitOnYes && launch == "YES" {
+		return exitNo
+	}
+	return exitOK
+}
+
+// exitPolicyFlag registers -exit-on on flags.
+func exitPolicyFlag(flags *flag.FlagSet) *exitPolicy {
+	policy := exitOnNo
+	flags.Var(&policy, "exit-on", "the decisions that give exit code 1: no, yes or error")
+	return &policy
+}
+
+// boundsValue is a bounding box flag: minX,minY,maxX,maxY. It leaves the
+// points unbounded until it is set.
+type boundsValue struct {
+	bounds **decide.Bounds
+}
+
+func (v boundsValue) String() string {
+	if v.bounds == nil || *v.bounds == nil {
+		return ""
+	}
+	b := **v.bounds
+	return fmt.Sprintf("%g,%g,%g,%g", b.Min[0], b.Min[1], b.Max[0], b.Max[1])
+}
+
+func (v boundsValue) Set(value string) error {
+	fields := strings.Split(value, ",")
+	if len(fields) != 4 {
+		return fmt.Errorf("must be minX,minY,maxX,maxY")
+	}
+	var c [4]float64
+	for i, field := range fields {
+		f, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return fmt.Errorf("must be minX,minY,maxX,maxY: %s", err)
+		}
+		if math.IsNaN(f) {
+			return fmt.Errorf("must be minX,minY,maxX,maxY, not NaN")
+		}
+		c[i] = f
+	}
+	if c[0] > c[2] || c[1] > c[3] {
+		return fmt.Errorf("the minimum is greater than the maximum")
+	}
+	*v.bounds = &decide.Bounds{Min: [2]float64{c[0], c[1]}, Max: [2]float64{c[2], c[3]}}
+	return nil
+}
+
+// boundsFlag registers -bounds on flags.
+func boundsFlag(flags *flag.FlagSet) **decide.Bounds {
+	var bounds *decide.Bounds
+	flags.Var(boundsValue{&bounds}, "bounds", "the bounding box of the coordinates of the points, minX,minY,maxX,maxY; unbounded when not set")
+	return &bounds
+}
+
+// evaluationValue is a flag.Value of an evaluation of the engine.
+type evaluationValue struct {
+	evaluation *decide.Evaluation
+}
+
+func (v evaluationValue) String() string {
+	if v.evaluation == nil {
+		return ""
+	}
+	return string(*v.evaluation)
+}
+
+func (v evaluationValue) Set(value string) error {
+	evaluation, err := parseEvaluation(value)
+	if err != nil {
+		return err
+	}
+	*v.evaluation = evaluation
+	return nil
+}
+
+func parseEvaluation(value string) (decide.Evaluation, error) {
+	var names []string
+	for _, evaluation := range decide.Evaluations {
+		if string(evaluation) == value {
+			return evaluation, nil
+		}
+		names = append(names, string(evaluation))
+	}
+	return "", fmt.Errorf("must be one of %s", strings.Join(names, ", "))
+}
+
+// evaluationFlag registers -evaluation on flags.
+func evaluationFlag(flags *flag.FlagSet) *decide.Evaluation {
+	evaluation := decide.EvaluationFloat
+	flags.Var(evaluationValue{&evaluation}, "evaluation", "how the LICs compare quantities with their thresholds: float, tolerant, exact or strict")
+	return &evaluation
+}
+
+// parseFlags parses args and returns the exit code to use when the
+// command must stop, or -1 when it can go on.
+func parseFlags(flags *flag.FlagSet, args []string) int {
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitInput
+	}
+	return -1
+}
+
+// newFlagSet creates the flag set of a command with a consistent help text.
+func newFlagSet(name string, args string, description string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: decide %s [flags] %s\n\n%s\n\nFlags:\n", name, args, description)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
 func getInput(inputPath string) (decide.INPUT, error) {
 	var input decide.INPUT
 	configFile, err := os.Open(inputPath)
-	defer configFile.Close()
 	if err != nil {
-		return input, err
+		return input, inputError{err}
 	}
+	defer configFile.Close()
 
 	jsonParser := json.NewDecoder(configFile)
 	if err = jsonParser.Decode(&input); err != nil {
-		return input, err
+		return input, inputError{err}
 	}
 	return input, nil
 }
 
-func serializeDecision(decision decide.Decide) []byte {
-	strOutput, err := json.MarshalIndent(decision, "", "  ")
+func getDecision(resultPath string) (decide.Decide, error) {
+	var decision decide.Decide
+	content, err := ioutil.ReadFile(resultPath)
 	if err != nil {
-		println("err output", err.Error())
-		return nil
+		return decision, inputError{err}
 	}
-	return strOutput
+	if err = json.Unmarshal(content, &decision); err != nil {
+		return decision, inputError{err}
+	}
+	return decision, nil
 }
 
-func execute(filePath string, outputDir string) decide.Decide {
-	decision := decide.Decide{}
-
-	input, err := getInput(filePath)
+// inputFiles lists the json files of filePath when it is a directory,
+// or filePath itself otherwise.
+func inputFiles(filePath string) ([]string, error) {
+	fi, err := os.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return []string{filePath}, nil
+	}
+	files, err := ioutil.ReadDir(filePath)
 	if err != nil {
-		println("unable to get the input file", err.Error())
-		return decision
+		return nil, err
+	}
+	var paths []string
+	for _, f := range files {
+		name := f.Name()
+		if (strings.Contains(name, ".json")) {
+			paths = append(paths, path.Join(filePath, name))
+		}
 	}
+	return paths, nil
+}
 
-	err = decision.Decide(input)
-	if outputDir != "" {
-		os.MkdirAll(outputDir, 0700)
-		outputFile := path.Join(outputDir, path.Base(filePath))
-		ioutil.WriteFile(outputFile, serializeDecision(decision), 0644)
+// newClock returns the source of the timestamps recorded in the provenance
+// of results: the timestamp flag when set (RFC 3339), then the
+// SOURCE_DATE_EPOCH environment variable for reproducible builds, then
+// the current time.
+func newClock(timestamp string) (func() time.Time, error) {
+	if timestamp != "" {
+		t, err := time.Parse(time.RFC3339, timestamp)
+		if err != nil {
+			return nil, inputError{err}
+		}
+		return func() time.Time { return t }, nil
 	}
-	return decision
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		seconds, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return nil, inputError{fmt.Errorf("invalid SOURCE_DATE_EPOCH: %s", err)}
+		}
+		t := time.Unix(seconds, 0)
+		return func() time.Time { return t }, nil
+	}
+	return time.Now, nil
+}
+
+// newLogger returns a logger writing JSON events of at least level
+// (debug, info, warn or error) to stderr.
+func newLogger(level string) (*slog.Logger, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return nil, inputError{err}
+	}
+	return slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: l})), nil
+}
+
+func serializeDecision(decision decide.Decide) ([]byte, error) {
+	return json.MarshalIndent(decision, "", "  ")
 }
 
 func main() {
-	filePath := flag.String("input", "", "the path to the input")
-	outputPath := flag.String("output", "", "the path to the output")
-	flag.Parse()
+	os.Exit(dispatch(os.Args[1:]))
+}
 
-	if *filePath == "" {
-		flag.Usage()
-		return
+func dispatch(args []string) (code int) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintln(os.Stderr, "decide: internal error:", r)
+			code = exitInternal
+		}
+	}()
+	// without a command, behave like the original single command CLI:
+	// decide -input file, decide -
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "-help" {
+		return runCmd(args)
 	}
-	f, err := os.Open(*filePath)
-	if err != nil {
-		fmt.Println(err)
-		return
+	name := args[0]
+	if name == "help" || name == "-h" || name == "-help" {
+		fmt.Print(usage)
+		return exitOK
 	}
-	defer f.Close()
-	fi, err := f.Stat()
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-	switch mode := fi.Mode(); {
-	case mode.IsDir():
-		files, _ := ioutil.ReadDir(*filePath)
-		for _, f := range files {
-			name := f.Name()
-			if (strings.Contains(name, ".json")) {
-				ff := *filePath + ""
-				file := path.Join(ff, name)
-				fmt.Print(name)
-				decide := execute(file, *outputPath)
-				fmt.Println(" " + decide.Launch)
-			}
+	for _, c := range commands {
+		if c.name == name {
+			return c.run(args[1:])
 		}
-	case mode.IsRegular():
-		decide := execute(*filePath, *outputPath)
-		fmt.Println(decide.Launch)
 	}
+	fmt.Fprintf(os.Stderr, "decide: unknown command %q\n\n%s", name, usage)
+	return exitInput
 }
@@ -0,0 +1,117 @@
+// Package metrics instruments the decision engine with Prometheus metrics.
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/tdurieux/go-decide/decide"
+)
+
+// Metrics implements decide.Observer. Set it as the Observer of an
+// Engine to record its evaluations.
+type Metrics struct {
+	registry         *prometheus.Registry
+	decisions        *prometheus.CounterVec
+	licEvaluations   *prometheus.CounterVec
+	licTrue          *prometheus.CounterVec
+	ruleDuration     *prometheus.HistogramVec
+	validationErrors *prometheus.CounterVec
+	inputPoints      prometheus.Histogram
+}
+
+// New returns metrics registered on their own registry. The histogram of
+// NUMPOINTS has buckets up to maxPoints, the maximum of the engine, or
+// decide.DefaultMaxPoints when it is 0.
+func New(maxPoints int) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		decisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "decide_decisions_total",
+			Help: "Evaluated inputs by outcome: YES, NO or ERROR.",
+		}, []string{"outcome"}),
+		licEvaluations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "decide_lic_evaluations_total",
+			Help: "Evaluations of each LIC without error.",
+		}, []string{"lic"}),
+		licTrue: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "decide_lic_true_total",
+			Help: "Evaluations of each LIC that are true; divide by decide_lic_evaluations_total for the true rate.",
+		}, []string{"lic"}),
+		ruleDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "decide_rule_duration_seconds",
+			Help:    "Duration of the evaluation of each LIC.",
+			Buckets: prometheus.ExponentialBuckets(1e-7, 4, 12),
+		}, []string{"lic"}),
+		validationErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "decide_validation_errors_total",
+			Help: "Rejected inputs by offending field.",
+		}, []string{"field"}),
+		inputPoints: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "decide_input_points",
+			Help:    "NUMPOINTS of the evaluated inputs.",
+			Buckets: pointBuckets(maxPoints),
+		}),
+	}
+	m.registry.MustRegister(m.decisions, m.licEvaluations, m.licTrue, m.ruleDuration,
+		m.validationErrors, m.inputPoints)
+	return m
+}
+
+// pointBuckets returns the buckets 2, 5, 10, 20, 50, 100, ... up to the
+// first one that holds maxPoints.
+func pointBuckets(maxPoints int) []float64 {
+	if maxPoints == 0 {
+		maxPoints = decide.DefaultMaxPoints
+	}
+	buckets := []float64{2}
+	for scale := 1.0; buckets[len(buckets) - 1] < float64(maxPoints); scale *= 10 {
+		buckets = append(buckets, 5 * scale, 10 * scale, 20 * scale)
+	}
+	for len(buckets) > 1 && buckets[len(buckets) - 2] >= float64(maxPoints) {
+		buckets = buckets[:len(buckets) - 1]
+	}
+	return buckets
+}
+
+// ObserveRule implements decide.Observer.
+func (m *Metrics) ObserveRule(lic int, value bool, elapsed time.Duration, err error) {
+	label := strconv.Itoa(lic)
+	m.ruleDuration.WithLabelValues(label).Observe(elapsed.Seconds())
+	if err != nil {
+		return
+	}
+	m.licEvaluations.WithLabelValues(label).Inc()
+	if value {
+		m.licTrue.WithLabelValues(label).Inc()
+	}
+}
+
+// ObserveDecision implements decide.Observer.
+func (m *Metrics) ObserveDecision(input decide.INPUT, d *decide.Decide, err error) {
+	m.inputPoints.Observe(float64(input.NumPoints))
+	if err == nil {
+		m.decisions.WithLabelValues(d.Launch).Inc()
+		return
+	}
+	m.decisions.WithLabelValues("ERROR").Inc()
+	var validation *decide.ValidationError
+	if errors.As(err, &validation) {
+		m.validationErrors.WithLabelValues(validation.Field).Inc()
+	}
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// WriteFile writes the metrics to path in the Prometheus text format,
+// e.g. for the textfile collector of the node exporter.
+func (m *Metrics) WriteFile(path string) error {
+	return prometheus.WriteToTextfile(path, m.registry)
+}
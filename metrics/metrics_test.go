@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tdurieux/go-decide/decide"
+)
+
+func TestMetrics(t *testing.T) {
+	m := New(0)
+	e := decide.Engine{Observer: m}
+	input := decide.Generate(31)
+	d, err := e.Decide(context.Background(), input)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	input.Parameters.Q_PTS = 1
+	if _, err := e.Decide(context.Background(), input); err == nil {
+		t.Error("Invalid Q_PTS expected")
+		return
+	}
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	expected := []string{
+		`decide_decisions_total{outcome="` + d.Launch + `"} 1`,
+		`decide_decisions_total{outcome="ERROR"} 1`,
+		`decide_validation_errors_total{field="Q_PTS"} 1`,
+		`decide_lic_evaluations_total{lic="14"} 1`,
+		`decide_rule_duration_seconds_count{lic="0"} 2`,
+		`decide_input_points_count 2`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(body, line) {
+			t.Error("Expected", line, "in", body)
+			return
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "decide.prom")
+	if err := m.WriteFile(path); err != nil {
+		t.Error(err)
+		return
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !strings.Contains(string(content), expected[0]) {
+		t.Error("Expected the metrics in the file", string(content))
+		return
+	}
+}
+
+func TestPointBuckets(t *testing.T) {
+	cases := map[int][]float64{
+		0:       {2, 5, 10, 20, 50, 100},
+		2:       {2},
+		150:     {2, 5, 10, 20, 50, 100, 200},
+		1000000: {2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000, 10000, 20000, 50000, 100000, 200000, 500000, 1000000},
+	}
+	for maxPoints, expected := range cases {
+		if buckets := pointBuckets(maxPoints); !reflect.DeepEqual(buckets, expected) {
+			t.Error("Expected the buckets", expected, "for", maxPoints, "got", buckets)
+			return
+		}
+	}
+}
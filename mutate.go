@@ -0,0 +1,101 @@
+package main
+
+import (
+	"github.com/tdurieux/go-decide/mutation"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+func mutateCmd(args []string) int {
+	flags := newFlagSet("mutate", "",
+		"Applies operator, boundary and constant mutations one at a time to a\n" +
+		"copy of a source file, runs the tests on each mutant and reports the\n" +
+		"mutants that survived them, by function. Run it from the root of the\n" +
+		"module.")
+	file := flags.String("file", "decide/decide.go", "the mutated file, relative to the module root")
+	testFile := flags.String("tests", "decide/decide_test.go", "run only the tests of this file, all the tests of the package when empty")
+	run := flags.String("run", "", "run only the tests matching this regexp, instead of those of -tests")
+	functions := flags.String("functions", "", "mutate only the functions matching this regexp, e.g. ^Rule")
+	workers := flags.Int("workers", runtime.NumCPU(), "the number of mutants tested at once")
+	timeout := flags.Duration("timeout", 0, "the maximum duration of the tests of a mutant, 1m when 0")
+	list := flags.Bool("list", false, "list the mutants without testing them")
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return exitInput
+	}
+	src, err := ioutil.ReadFile(*file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInput
+	}
+	mutants, err := mutation.Mutants(*file, src)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInput
+	}
+	if *functions != "" {
+		pattern, err := regexp.Compile(*functions)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "invalid -functions:", err)
+			return exitInput
+		}
+		var selected []mutation.Mutant
+		for _, m := range mutants {
+			if pattern.MatchString(m.Function) {
+				selected = append(selected, m)
+			}
+		}
+		mutants = selected
+	}
+	if *list {
+		for _, m := range mutants {
+			fmt.Printf("%d\t%s\t%s\n", m.ID, m.Function, m)
+		}
+		return exitOK
+	}
+
+	config := mutation.Config{Root: ".", File: filepath.Clean(*file), Run: *run, Timeout: *timeout, Workers: *workers}
+	if config.Run == "" && *testFile != "" {
+		names, err := mutation.TestNames(*testFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitInput
+		}
+		config.Run = "^(" + strings.Join(names, "|") + ")$"
+	}
+	done := 0
+	report, err := mutation.Run(context.Background(), config, mutants, func(r mutation.Result) {
+		done++
+		fmt.Fprintf(os.Stderr, "[%d/%d] %s %s %s\n", done, len(mutants), r.Status, r.Function, r.Mutant)
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInternal
+	}
+	if *asJSON {
+		content, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitInternal
+		}
+		fmt.Println(string(content))
+	} else if err := report.WriteText(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInternal
+	}
+	if len(report.Survivors()) != 0 {
+		return exitNo
+	}
+	return exitOK
+}
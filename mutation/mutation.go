@@ -0,0 +1,222 @@
+// Package mutation applies small mutations to a Go source file and
+// reports those its tests do not detect.
+package mutation
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of mutations.
+const (
+	// KindOperator replaces an arithmetic, logical or equality operator.
+	KindOperator = "operator"
+	// KindBoundary makes a comparison strict or not.
+	KindBoundary = "boundary"
+	// KindConstant changes a number literal, or swaps the values of two
+	// string constants of the same type.
+	KindConstant = "constant"
+)
+
+// Edit replaces Length bytes at Offset of the source with Text.
+type Edit struct {
+	Offset int
+	Length int
+	Text   string
+}
+
+// Mutant is a mutation of a source file.
+type Mutant struct {
+	ID   int    `json:"ID"`
+	Kind string `json:"KIND"`
+	// Function is the function the mutation is in, or "const" and "var"
+	// for the declarations of the package.
+	Function string `json:"FUNCTION"`
+	Line     int    `json:"LINE"`
+	Column   int    `json:"COLUMN"`
+	// Original and Replacement describe the mutation, e.g. ">" and ">=".
+	Original    string `json:"ORIGINAL"`
+	Replacement string `json:"REPLACEMENT"`
+	Edits       []Edit `json:"-"`
+}
+
+func (m Mutant) String() string {
+	return fmt.Sprintf("%d:%d %s: %s -> %s", m.Line, m.Column, m.Kind, m.Original, m.Replacement)
+}
+
+// Apply returns src with the mutation applied. src must be the source
+// the mutant was found in.
+func (m Mutant) Apply(src []byte) []byte {
+	edits := append([]Edit(nil), m.Edits...)
+	sort.Slice(edits, func(i, j int) bool { return edits[i].Offset > edits[j].Offset })
+	out := append([]byte(nil), src...)
+	for _, e := range edits {
+		out = append(out[:e.Offset], append([]byte(e.Text), out[e.Offset + e.Length:]...)...)
+	}
+	return out
+}
+
+// operators maps each mutated operator to its replacement and kind.
+var operators = map[token.Token]struct {
+	replacement token.Token
+	kind        string
+}{
+	token.LSS:  {token.LEQ, KindBoundary},
+	token.LEQ:  {token.LSS, KindBoundary},
+	token.GTR:  {token.GEQ, KindBoundary},
+	token.GEQ:  {token.GTR, KindBoundary},
+	token.ADD:  {token.SUB, KindOperator},
+	token.SUB:  {token.ADD, KindOperator},
+	token.MUL:  {token.QUO, KindOperator},
+	token.QUO:  {token.MUL, KindOperator},
+	token.LAND: {token.LOR, KindOperator},
+	token.LOR:  {token.LAND, KindOperator},
+	token.EQL:  {token.NEQ, KindOperator},
+	token.NEQ:  {token.EQL, KindOperator},
+}
+
+// Mutants returns the mutants of the Go source src, in source order.
+// The array lengths of types are never mutated.
+func Mutants(filename string, src []byte) ([]Mutant, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, 0)
+	if err != nil {
+		return nil, err
+	}
+	var mutants []Mutant
+	add := func(function string, kind string, pos token.Pos, original string, replacement string, edits ...Edit) {
+		p := fset.Position(pos)
+		mutants = append(mutants, Mutant{
+			Kind: kind, Function: function, Line: p.Line, Column: p.Column,
+			Original: original, Replacement: replacement, Edits: edits,
+		})
+	}
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+	inspect := func(function string, node ast.Node) {
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.ArrayType:
+				return false
+			case *ast.BinaryExpr:
+				if op, ok := operators[n.Op]; ok {
+					add(function, op.kind, n.OpPos, n.Op.String(), op.replacement.String(),
+						Edit{offset(n.OpPos), len(n.Op.String()), op.replacement.String()})
+				}
+			case *ast.BasicLit:
+				if replacement, ok := nextNumber(n); ok {
+					add(function, KindConstant, n.Pos(), n.Value, replacement,
+						Edit{offset(n.Pos()), len(n.Value), replacement})
+				}
+			}
+			return true
+		})
+	}
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Body != nil {
+				inspect(decl.Name.Name, decl.Body)
+			}
+		case *ast.GenDecl:
+			switch decl.Tok {
+			case token.CONST:
+				for _, spec := range decl.Specs {
+					for _, value := range spec.(*ast.ValueSpec).Values {
+						inspect("const", value)
+					}
+				}
+				for _, pair := range stringPairs(decl) {
+					a, b := pair[0], pair[1]
+					add("const", KindConstant, a.Pos(), a.Value + ", " + b.Value, b.Value + ", " + a.Value,
+						Edit{offset(a.Pos()), len(a.Value), b.Value},
+						Edit{offset(b.Pos()), len(b.Value), a.Value})
+				}
+			case token.VAR:
+				for _, spec := range decl.Specs {
+					for _, value := range spec.(*ast.ValueSpec).Values {
+						inspect("var", value)
+					}
+				}
+			}
+		}
+	}
+	sort.SliceStable(mutants, func(i, j int) bool {
+		if mutants[i].Line != mutants[j].Line {
+			return mutants[i].Line < mutants[j].Line
+		}
+		return mutants[i].Column < mutants[j].Column
+	})
+	for i := range mutants {
+		mutants[i].ID = i + 1
+	}
+	return mutants, nil
+}
+
+// nextNumber returns the number literal lit plus one.
+func nextNumber(lit *ast.BasicLit) (string, bool) {
+	switch lit.Kind {
+	case token.INT:
+		n, err := strconv.ParseInt(lit.Value, 0, 64)
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatInt(n + 1, 10), true
+	case token.FLOAT:
+		f, err := strconv.ParseFloat(lit.Value, 64)
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatFloat(f + 1, 'g', -1, 64), true
+	}
+	return "", false
+}
+
+// stringPairs returns the pairs of consecutive string constants of decl
+// that have the same explicit type, such as the values of an enumeration.
+func stringPairs(decl *ast.GenDecl) [][2]*ast.BasicLit {
+	var pairs [][2]*ast.BasicLit
+	var previous *ast.BasicLit
+	var previousType string
+	for _, spec := range decl.Specs {
+		vs := spec.(*ast.ValueSpec)
+		ident, ok := vs.Type.(*ast.Ident)
+		if !ok || len(vs.Values) != 1 {
+			previous = nil
+			continue
+		}
+		lit, ok := vs.Values[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			previous = nil
+			continue
+		}
+		if previous != nil && previousType == ident.Name {
+			pairs = append(pairs, [2]*ast.BasicLit{previous, lit})
+		}
+		previous, previousType = lit, ident.Name
+	}
+	return pairs
+}
+
+// TestNames returns the names of the test functions of the Go test file
+// at path, e.g. to run only the tests of that file.
+func TestNames(path string) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, decl := range file.Decls {
+		f, ok := decl.(*ast.FuncDecl)
+		if ok && f.Recv == nil && strings.HasPrefix(f.Name.Name, "Test") && f.Name.Name != "TestMain" {
+			names = append(names, f.Name.Name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,195 @@
+package mutation
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const source = `package max
+
+type Kind string
+
+const (
+	Low  Kind = "LOW"
+	High Kind = "HIGH"
+)
+
+var sizes = [2]int{1, 2}
+
+// Max returns the largest of a and b.
+func Max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func Twice(a int) int {
+	return a * 2
+}
+
+func Name(a int, b int) string {
+	if Max(a, b) == a {
+		return string(High) + "!"
+	}
+	return string(Low)
+}
+`
+
+const tests = `package max
+
+import "testing"
+
+func TestMax(t *testing.T) {
+	if Max(2, 1) != 2 || Max(1, 2) != 2 {
+		t.Error("wrong max")
+	}
+}
+
+func TestTwice(t *testing.T) {
+	if Twice(3) != 6 {
+		t.Error("wrong double")
+	}
+}
+`
+
+func find(mutants []Mutant, function string, original string, replacement string) *Mutant {
+	for i, m := range mutants {
+		if m.Function == function && m.Original == original && m.Replacement == replacement {
+			return &mutants[i]
+		}
+	}
+	return nil
+}
+
+func TestMutants(t *testing.T) {
+	mutants, err := Mutants("max.go", []byte(source))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	boundary := find(mutants, "Max", ">", ">=")
+	if boundary == nil || boundary.Kind != KindBoundary || boundary.Line != 14 {
+		t.Error("Expected a boundary mutant of Max on line 14, got", mutants)
+		return
+	}
+	if !strings.Contains(string(boundary.Apply([]byte(source))), "if a >= b {") {
+		t.Error("Expected the mutant to make the comparison non strict")
+		return
+	}
+	swap := find(mutants, "const", `"LOW", "HIGH"`, `"HIGH", "LOW"`)
+	if swap == nil {
+		t.Error("Expected the string constants to be swapped, got", mutants)
+		return
+	}
+	mutated := string(swap.Apply([]byte(source)))
+	if !strings.Contains(mutated, `Low  Kind = "HIGH"`) || !strings.Contains(mutated, `High Kind = "LOW"`) {
+		t.Error("Expected the values of Low and High to be swapped, got", mutated)
+		return
+	}
+	if find(mutants, "Twice", "*", "/") == nil || find(mutants, "Twice", "2", "3") == nil || find(mutants, "var", "1", "2") == nil {
+		t.Error("Expected operator and constant mutants, got", mutants)
+		return
+	}
+	for _, m := range mutants {
+		if m.Function == "var" && m.Original == "2" && m.Column == 14 {
+			t.Error("Expected the length of the array type not to be mutated")
+			return
+		}
+	}
+	if mutants[0].ID != 1 || mutants[0].Line > mutants[len(mutants) - 1].Line {
+		t.Error("Expected the mutants in source order")
+		return
+	}
+}
+
+func TestTestNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "max_test.go")
+	if err := ioutil.WriteFile(path, []byte(tests), 0644); err != nil {
+		t.Fatal(err)
+	}
+	names, err := TestNames(path)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if strings.Join(names, ",") != "TestMax,TestTwice" {
+		t.Error("Expected the tests of the file, got", names)
+		return
+	}
+}
+
+func TestRun(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":          "module example.com/max\n\ngo 1.21\n",
+		"max/max.go":      source,
+		"max/max_test.go": tests,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mutants, err := Mutants("max.go", []byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var selected []Mutant
+	for _, m := range []*Mutant{
+		find(mutants, "Max", ">", ">="),
+		find(mutants, "Twice", "*", "/"),
+		find(mutants, "Name", "+", "-"),
+	} {
+		selected = append(selected, *m)
+	}
+
+	config := Config{Root: root, File: "max/max.go", Workers: 2}
+	progressed := 0
+	report, err := Run(context.Background(), config, selected, func(Result) { progressed++ })
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	statuses := []string{StatusSurvived, StatusKilled, StatusInvalid}
+	for i, result := range report.Results {
+		if result.Status != statuses[i] {
+			t.Error("Expected mutant", result.Mutant, "to be", statuses[i], "got", result.Status)
+			return
+		}
+	}
+	if progressed != 3 || report.Functions["Max"].Survived != 1 || report.Score() != 0.5 {
+		t.Error("Expected one survivor in Max and a score of 50%, got", report.Functions, report.Score())
+		return
+	}
+	var b strings.Builder
+	if err := report.WriteText(&b); err != nil {
+		t.Error(err)
+		return
+	}
+	if !strings.Contains(b.String(), "Max: 1 survived") || strings.Contains(b.String(), "Twice") {
+		t.Error("Expected only the survivors to be listed, got", b.String())
+		return
+	}
+	content, err := ioutil.ReadFile(filepath.Join(root, "max/max.go"))
+	if err != nil || string(content) != source {
+		t.Error("Expected the original file not to be modified")
+		return
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(root, "max/max_test.go"), []byte(strings.Replace(tests, "!= 6", "!= 7", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(context.Background(), config, selected, nil); err == nil {
+		t.Error("Expected failing tests to be reported before any mutation")
+		return
+	}
+}
@@ -0,0 +1,245 @@
+package mutation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status of a mutant once its tests ran.
+const (
+	// StatusKilled is a mutant that fails the tests.
+	StatusKilled = "KILLED"
+	// StatusSurvived is a mutant that passes the tests.
+	StatusSurvived = "SURVIVED"
+	// StatusInvalid is a mutant that does not compile.
+	StatusInvalid = "INVALID"
+)
+
+// Config locates the mutated file and its tests.
+type Config struct {
+	// Root is the root of the module, copied to a temporary directory
+	// for each worker.
+	Root string
+	// File is the mutated file, relative to Root.
+	File string
+	// Run selects the tests like go test -run, all of them when empty.
+	Run string
+	// Timeout bounds the tests of each mutant; a mutant that exceeds it
+	// is killed. 1 minute when 0.
+	Timeout time.Duration
+	// Workers is the number of mutants tested at once, 1 when 0.
+	Workers int
+}
+
+// Result is a mutant and its status.
+type Result struct {
+	Mutant
+	Status string `json:"STATUS"`
+}
+
+// Counts counts the mutants of a function by status.
+type Counts struct {
+	Killed   int `json:"KILLED"`
+	Survived int `json:"SURVIVED"`
+	Invalid  int `json:"INVALID"`
+}
+
+// Report summarizes a mutation run.
+type Report struct {
+	File    string   `json:"FILE"`
+	Results []Result `json:"RESULTS"`
+	// Functions counts the mutants of each function.
+	Functions map[string]Counts `json:"FUNCTIONS"`
+}
+
+// Score returns the share of the valid mutants that were killed, 1 when
+// there is none.
+func (r Report) Score() float64 {
+	killed, valid := 0, 0
+	for _, c := range r.Functions {
+		killed += c.Killed
+		valid += c.Killed + c.Survived
+	}
+	if valid == 0 {
+		return 1
+	}
+	return float64(killed) / float64(valid)
+}
+
+// Survivors returns the mutants that passed the tests.
+func (r Report) Survivors() []Result {
+	var survivors []Result
+	for _, result := range r.Results {
+		if result.Status == StatusSurvived {
+			survivors = append(survivors, result)
+		}
+	}
+	return survivors
+}
+
+// WriteText writes the counts of each function that has a surviving
+// mutant, with its survivors, followed by the score.
+func (r Report) WriteText(w io.Writer) error {
+	var b bytes.Buffer
+	functions := make([]string, 0, len(r.Functions))
+	for function, c := range r.Functions {
+		if c.Survived != 0 {
+			functions = append(functions, function)
+		}
+	}
+	sort.Strings(functions)
+	survivors := r.Survivors()
+	for _, function := range functions {
+		c := r.Functions[function]
+		fmt.Fprintf(&b, "%s: %d survived, %d killed, %d invalid\n", function, c.Survived, c.Killed, c.Invalid)
+		for _, s := range survivors {
+			if s.Function == function {
+				fmt.Fprintf(&b, "\t%s:%s\n", r.File, s.Mutant)
+			}
+		}
+	}
+	fmt.Fprintf(&b, "%d mutants, %d survived, score %.1f%%\n", len(r.Results), len(survivors), 100 * r.Score())
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// Run tests each mutant of the file of config in a copy of its module
+// and reports which ones the tests kill. progress, when not nil, is
+// called after each mutant. An error is returned when the tests fail
+// without mutation.
+func Run(ctx context.Context, config Config, mutants []Mutant, progress func(Result)) (Report, error) {
+	report := Report{File: config.File, Results: make([]Result, len(mutants)), Functions: map[string]Counts{}}
+	if config.Timeout == 0 {
+		config.Timeout = time.Minute
+	}
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+	src, err := ioutil.ReadFile(filepath.Join(config.Root, config.File))
+	if err != nil {
+		return report, err
+	}
+
+	copies := make([]string, config.Workers)
+	for i := range copies {
+		dir, err := ioutil.TempDir("", "mutation")
+		if err != nil {
+			return report, err
+		}
+		defer os.RemoveAll(dir)
+		if err := copyTree(config.Root, dir); err != nil {
+			return report, err
+		}
+		copies[i] = dir
+	}
+	if status, output := test(ctx, config, copies[0]); status != StatusSurvived {
+		return report, fmt.Errorf("the tests fail without mutation:\n%s", output)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for _, dir := range copies {
+		wg.Add(1)
+		go func(dir string) {
+			defer wg.Done()
+			target := filepath.Join(dir, config.File)
+			for i := range next {
+				status := StatusInvalid
+				if err := ioutil.WriteFile(target, mutants[i].Apply(src), 0644); err == nil {
+					status, _ = test(ctx, config, dir)
+				}
+				result := Result{mutants[i], status}
+				mu.Lock()
+				report.Results[i] = result
+				if progress != nil {
+					progress(result)
+				}
+				mu.Unlock()
+			}
+			ioutil.WriteFile(target, src, 0644)
+		}(dir)
+	}
+	for i := range mutants {
+		if ctx.Err() != nil {
+			break
+		}
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+
+	for _, result := range report.Results {
+		c := report.Functions[result.Function]
+		switch result.Status {
+		case StatusKilled:
+			c.Killed++
+		case StatusSurvived:
+			c.Survived++
+		default:
+			c.Invalid++
+		}
+		report.Functions[result.Function] = c
+	}
+	return report, nil
+}
+
+// test runs the tests of the package of the mutated file in dir.
+func test(ctx context.Context, config Config, dir string) (string, []byte) {
+	args := []string{"test", "-count=1", "-failfast", "-vet=off", "-timeout", config.Timeout.String()}
+	if config.Run != "" {
+		args = append(args, "-run", config.Run)
+	}
+	args = append(args, "./" + filepath.ToSlash(filepath.Dir(config.File)))
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	switch {
+	case err == nil:
+		return StatusSurvived, output
+	case bytes.Contains(output, []byte("[build failed]")) || bytes.Contains(output, []byte("[setup failed]")):
+		return StatusInvalid, output
+	}
+	return StatusKilled, output
+}
+
+// copyTree copies the regular files of src to dst, except those of the
+// .git directory.
+func copyTree(src string, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0755)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(target, content, info.Mode().Perm())
+	})
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"github.com/tdurieux/go-decide/decide"
+	"github.com/tdurieux/go-decide/render"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func renderCmd(args []string) int {
+	flags := newFlagSet("render", "input",
+		"Decides an input and draws its points as an SVG polyline with their\n" +
+		"indices and the quadrant axes, and for each LIC met the set of points\n" +
+		"that satisfies it: the segment, the circle of RADIUS1 or RADIUS2, the\n" +
+		"triangle and its area, or the angle compared with EPSILON. A click on\n" +
+		"a LIC of the legend shows or hides its overlay.")
+	output := flags.String("o", "", "the SVG file to write, stdout when empty")
+	lics := flags.String("lics", "", "the comma-separated LICs whose overlays are drawn, all those met when empty")
+	width := flags.Int("width", 800, "the width of the drawing")
+	height := flags.Int("height", 600, "the height of the drawing")
+	evaluation := evaluationFlag(flags)
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitInput
+	}
+	opts := render.Options{Width: *width, Height: *height}
+	if *lics != "" {
+		opts.LICs = []int{}
+		for _, field := range strings.Split(*lics, ",") {
+			lic, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || lic < 0 || lic >= decide.NB_LIC {
+				fmt.Fprintf(os.Stderr, "invalid -lics: %q is not a LIC\n", field)
+				return exitInput
+			}
+			opts.LICs = append(opts.LICs, lic)
+		}
+	}
+
+	input, err := getInput(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "unable to get the input file", err.Error())
+		return exitCode(err)
+	}
+	engine := &decide.Engine{Evaluation: *evaluation}
+	decision, err := engine.Decide(context.Background(), input)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitCode(err)
+	}
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitInternal
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := render.SVG(w, input, decision, opts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInternal
+	}
+	return exitOK
+}
@@ -0,0 +1,288 @@
+// Package render draws the points of an input and the witnesses of the
+// LICs of its decision as SVG.
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/tdurieux/go-decide/decide"
+)
+
+// Options selects what SVG draws.
+type Options struct {
+	// Width and Height are the size of the drawing, 800 by 600 when 0.
+	Width  int
+	Height int
+	// LICs selects the LICs whose witnesses are drawn, all those met when
+	// nil.
+	LICs []int
+	// ID prefixes the ids of the elements, to embed several drawings in
+	// the same page.
+	ID string
+}
+
+// padding is the margin around the points, in pixels.
+const padding = 40
+
+// Color returns the color of the overlays of a LIC.
+func Color(lic int) string {
+	return fmt.Sprintf("hsl(%d, 70%%, 42%%)", lic * 360 / decide.NB_LIC)
+}
+
+// canvas maps the coordinates of the points to the drawing.
+type canvas struct {
+	b      bytes.Buffer
+	width  float64
+	height float64
+	min    [2]float64
+	scale  float64
+	offset [2]float64
+}
+
+func newCanvas(points [][2]float64, width int, height int) *canvas {
+	c := &canvas{width: float64(width), height: float64(height)}
+	// The origin is always shown, for the quadrants.
+	max := [2]float64{0, 0}
+	for _, p := range points {
+		for k := 0; k < 2; k++ {
+			c.min[k] = math.Min(c.min[k], p[k])
+			max[k] = math.Max(max[k], p[k])
+		}
+	}
+	span := [2]float64{max[0] - c.min[0], max[1] - c.min[1]}
+	for k := range span {
+		if span[k] == 0 {
+			span[k] = 1
+		}
+	}
+	c.scale = math.Min((c.width - 2 * padding) / span[0], (c.height - 2 * padding) / span[1])
+	c.offset = [2]float64{
+		(c.width - 2 * padding - span[0] * c.scale) / 2,
+		(c.height - 2 * padding - span[1] * c.scale) / 2,
+	}
+	return c
+}
+
+// at returns the position of p in the drawing.
+func (c *canvas) at(p [2]float64) (float64, float64) {
+	return padding + c.offset[0] + (p[0] - c.min[0]) * c.scale,
+		c.height - padding - c.offset[1] - (p[1] - c.min[1]) * c.scale
+}
+
+func f(x float64) string {
+	return strconv.FormatFloat(x, 'f', 2, 64)
+}
+
+func (c *canvas) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&c.b, format, args...)
+}
+
+func (c *canvas) line(p [2]float64, q [2]float64, attrs string) {
+	x1, y1 := c.at(p)
+	x2, y2 := c.at(q)
+	c.printf(`<line x1="%s" y1="%s" x2="%s" y2="%s" %s/>`+"\n", f(x1), f(y1), f(x2), f(y2), attrs)
+}
+
+func (c *canvas) polygon(points [][2]float64, attrs string) {
+	c.printf(`<polygon points="`)
+	for i, p := range points {
+		x, y := c.at(p)
+		if i > 0 {
+			c.printf(" ")
+		}
+		c.printf("%s,%s", f(x), f(y))
+	}
+	c.printf(`" %s/>`+"\n", attrs)
+}
+
+func (c *canvas) text(p [2]float64, dx float64, dy float64, label string, attrs string) {
+	x, y := c.at(p)
+	c.printf(`<text x="%s" y="%s" %s>%s</text>`+"\n", f(x + dx), f(y + dy), attrs, label)
+}
+
+// circle draws the circle of radius r, in the unit of the points, around
+// the centroid of points.
+func (c *canvas) circle(points [][2]float64, r float64, attrs string) {
+	x, y := c.at(centroid(points))
+	c.printf(`<circle cx="%s" cy="%s" r="%s" fill="none" %s/>`+"\n", f(x), f(y), f(r * c.scale), attrs)
+}
+
+// arc draws the angle at vertex b between a and c.
+func (c *canvas) arc(a [2]float64, b [2]float64, cc [2]float64, attrs string) {
+	ax, ay := c.at(a)
+	bx, by := c.at(b)
+	cx, cy := c.at(cc)
+	from := math.Atan2(ay - by, ax - bx)
+	to := math.Atan2(cy - by, cx - bx)
+	delta := math.Remainder(to - from, 2 * math.Pi)
+	sweep := 0
+	if delta > 0 {
+		sweep = 1
+	}
+	const radius = 18
+	c.printf(`<path d="M %s %s A %d %d 0 0 %d %s %s" fill="none" %s/>`+"\n",
+		f(bx + radius * math.Cos(from)), f(by + radius * math.Sin(from)), radius, radius, sweep,
+		f(bx + radius * math.Cos(from + delta)), f(by + radius * math.Sin(from + delta)), attrs)
+}
+
+func centroid(points [][2]float64) [2]float64 {
+	var sum [2]float64
+	for _, p := range points {
+		sum[0] += p[0]
+		sum[1] += p[1]
+	}
+	return [2]float64{sum[0] / float64(len(points)), sum[1] / float64(len(points))}
+}
+
+func distance(p [2]float64, q [2]float64) float64 {
+	return math.Hypot(p[0] - q[0], p[1] - q[1])
+}
+
+func area(p [][2]float64) float64 {
+	return math.Abs((p[1][0] - p[0][0]) * (p[2][1] - p[0][1]) - (p[2][0] - p[0][0]) * (p[1][1] - p[0][1])) / 2
+}
+
+// SVG draws the points of input as a polyline with their indices and the
+// quadrant axes, and the witnesses of the LICs met by d, which must be
+// the decision of input made by Decide. Each overlay is a group with the
+// id "lic-n", shown or hidden by a click on its entry of the legend.
+func SVG(w io.Writer, input decide.INPUT, d decide.Decide, opts Options) error {
+	if opts.Width == 0 {
+		opts.Width = 800
+	}
+	if opts.Height == 0 {
+		opts.Height = 600
+	}
+	selected := make(map[int]bool)
+	for _, lic := range opts.LICs {
+		selected[lic] = true
+	}
+	c := newCanvas(input.Points, opts.Width, opts.Height)
+	c.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`+"\n",
+		opts.Width, opts.Height, opts.Width, opts.Height)
+	c.printf("<title>%d points, LAUNCH %s</title>\n", len(input.Points), d.Launch)
+	c.printf(`<rect width="100%%" height="100%%" fill="white"/>` + "\n")
+
+	// Quadrant axes through the origin.
+	ox, oy := c.at([2]float64{0, 0})
+	c.printf(`<g class="axes" stroke="#bbb">` + "\n")
+	c.printf(`<line x1="0" y1="%s" x2="%d" y2="%s"/>`+"\n", f(oy), opts.Width, f(oy))
+	c.printf(`<line x1="%s" y1="0" x2="%s" y2="%d"/>`+"\n", f(ox), f(ox), opts.Height)
+	c.printf("</g>\n")
+	c.printf(`<g class="quadrants" fill="#999">` + "\n")
+	c.printf(`<text x="%s" y="%s">Q1</text>`+"\n", f(ox + 6), f(oy - 6))
+	c.printf(`<text x="%s" y="%s" text-anchor="end">Q2</text>`+"\n", f(ox - 6), f(oy - 6))
+	c.printf(`<text x="%s" y="%s" text-anchor="end">Q3</text>`+"\n", f(ox - 6), f(oy + 14))
+	c.printf(`<text x="%s" y="%s">Q4</text>`+"\n", f(ox + 6), f(oy + 14))
+	c.printf("</g>\n")
+
+	// The points.
+	c.printf(`<g class="points">` + "\n")
+	c.printf(`<polyline fill="none" stroke="#333" stroke-width="1" points="`)
+	for i, p := range input.Points {
+		x, y := c.at(p)
+		if i > 0 {
+			c.printf(" ")
+		}
+		c.printf("%s,%s", f(x), f(y))
+	}
+	c.printf(`"/>` + "\n")
+	for i, p := range input.Points {
+		x, y := c.at(p)
+		c.printf(`<circle cx="%s" cy="%s" r="2.5" fill="#333"/>`+"\n", f(x), f(y))
+		c.text(p, 4, -4, strconv.Itoa(i), `fill="#333"`)
+	}
+	c.printf("</g>\n")
+
+	// The witnesses.
+	var drawn []int
+	for _, witness := range d.Witnesses() {
+		if opts.LICs != nil && !selected[witness.LIC] {
+			continue
+		}
+		drawn = append(drawn, witness.LIC)
+		c.printf(`<g id="%slic-%d" class="lic" stroke="%s" fill="%s" stroke-width="2">`+"\n", opts.ID, witness.LIC, Color(witness.LIC), Color(witness.LIC))
+		c.witness(input, witness)
+		c.printf("</g>\n")
+	}
+
+	// The legend.
+	for i, lic := range drawn {
+		y := 16 + 16 * i
+		c.printf(`<g class="legend" style="cursor: pointer" onclick="var g = document.getElementById('%slic-%d'); g.style.display = g.style.display == 'none' ? '' : 'none'">`+"\n", opts.ID, lic)
+		c.printf(`<rect x="8" y="%d" width="10" height="10" fill="%s"/>`+"\n", y - 9, Color(lic))
+		c.printf(`<text x="22" y="%d">LIC %d</text>`+"\n", y, lic)
+		c.printf("</g>\n")
+	}
+	c.printf("</svg>\n")
+	_, err := w.Write(c.b.Bytes())
+	return err
+}
+
+// witness draws the overlay of a witness.
+func (c *canvas) witness(input decide.INPUT, w decide.Witness) {
+	p := input.Parameters
+	set := func(indices []int) [][2]float64 {
+		points := make([][2]float64, len(indices))
+		for i, index := range indices {
+			points[i] = input.Points[index]
+		}
+		return points
+	}
+	first := set(w.Points)
+	var second [][2]float64
+	if w.Second != nil {
+		second = set(w.Second)
+	}
+	const dashed = `stroke-dasharray="6 3"`
+	switch w.LIC {
+	case 0, 7, 12:
+		c.line(first[0], first[1], "")
+		c.text(centroid(first), 4, 12, fmt.Sprintf("%.4g &gt; LENGTH1", distance(first[0], first[1])), `stroke="none"`)
+		if second != nil {
+			c.line(second[0], second[1], dashed)
+			c.text(centroid(second), 4, 24, fmt.Sprintf("%.4g &lt; LENGTH2", distance(second[0], second[1])), `stroke="none"`)
+		}
+	case 1, 8, 13:
+		c.polygon(first, `fill="none" `+dashed)
+		c.circle(first, p.RADIUS1, "")
+		c.text(centroid(first), 4, 12, fmt.Sprintf("RADIUS1 %.4g", p.RADIUS1), `stroke="none"`)
+		if second != nil {
+			c.polygon(second, `fill="none" `+dashed)
+			c.circle(second, p.RADIUS2, dashed)
+			c.text(centroid(second), 4, 24, fmt.Sprintf("RADIUS2 %.4g", p.RADIUS2), `stroke="none"`)
+		}
+	case 2, 9:
+		c.line(first[0], first[1], "")
+		c.line(first[1], first[2], "")
+		c.arc(first[0], first[1], first[2], "")
+		c.text(first[1], 8, 16, fmt.Sprintf("EPSILON %.4g", p.EPSILON), `stroke="none"`)
+	case 3, 10, 14:
+		c.polygon(first, `fill-opacity="0.2"`)
+		c.text(centroid(first), 0, 0, fmt.Sprintf("area %.4g &gt; AREA1", area(first)), `stroke="none" text-anchor="middle"`)
+		if second != nil {
+			c.polygon(second, `fill-opacity="0.1" `+dashed)
+			c.text(centroid(second), 0, 12, fmt.Sprintf("area %.4g &lt; AREA2", area(second)), `stroke="none" text-anchor="middle"`)
+		}
+	case 4:
+		for _, q := range first {
+			x, y := c.at(q)
+			c.printf(`<circle cx="%s" cy="%s" r="6" fill="none"/>`+"\n", f(x), f(y))
+		}
+		c.text(first[0], 8, 16, fmt.Sprintf("more than %d quadrants", p.QUADS), `stroke="none"`)
+	case 5, 11:
+		c.line(first[0], first[1], dashed)
+		c.text(first[1], 8, 16, "x decreases", `stroke="none"`)
+	case 6:
+		c.line(first[0], first[len(first) - 1], dashed)
+		for _, q := range first {
+			x, y := c.at(q)
+			c.printf(`<circle cx="%s" cy="%s" r="5" fill="none"/>`+"\n", f(x), f(y))
+		}
+		c.text(first[0], 8, 16, fmt.Sprintf("DIST %.4g", p.DIST), `stroke="none"`)
+	}
+}
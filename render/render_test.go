@@ -0,0 +1,101 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tdurieux/go-decide/decide"
+)
+
+// wellFormed checks that content is a well-formed XML document.
+func wellFormed(content []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func TestSVG(t *testing.T) {
+	for seed := int64(0); seed < 30; seed++ {
+		input := decide.Generate(seed)
+		d := decide.Decide{}
+		if err := d.Decide(input); err != nil {
+			t.Error(err)
+			return
+		}
+		var b bytes.Buffer
+		if err := SVG(&b, input, d, Options{}); err != nil {
+			t.Error(err)
+			return
+		}
+		if err := wellFormed(b.Bytes()); err != nil {
+			t.Error("Expected a well-formed SVG for seed", seed, "got", err)
+			return
+		}
+		svg := b.String()
+		witnesses := d.Witnesses()
+		if strings.Count(svg, `class="lic"`) != len(witnesses) || strings.Count(svg, `class="legend"`) != len(witnesses) {
+			t.Error("Expected an overlay and a legend entry for each of the", len(witnesses), "witnesses of seed", seed)
+			return
+		}
+		if strings.Count(svg, "<text") < input.NumPoints {
+			t.Error("Expected the points to be labelled with their index for seed", seed)
+			return
+		}
+		if len(witnesses) == 0 {
+			continue
+		}
+
+		lic := witnesses[len(witnesses) - 1].LIC
+		b.Reset()
+		if err := SVG(&b, input, d, Options{LICs: []int{lic}, ID: "a-", Width: 300, Height: 200}); err != nil {
+			t.Error(err)
+			return
+		}
+		svg = b.String()
+		if strings.Count(svg, `class="lic"`) != 1 || !strings.Contains(svg, `id="a-lic-` + strconv.Itoa(lic) + `"`) || !strings.Contains(svg, `width="300" height="200"`) {
+			t.Error("Expected only the overlay of the selected LIC, got", svg)
+			return
+		}
+	}
+}
+
+func TestSVGOverlays(t *testing.T) {
+	input := decide.Generate(1)
+	input.NumPoints = 5
+	input.Points = [][2]float64{{0, 0}, {4, 0}, {4, 3}, {-2, 1}, {-3, -2}}
+	input.Parameters = decide.Parameters{
+		LENGTH1: 1, LENGTH2: 10, RADIUS1: 1, RADIUS2: 10, EPSILON: 0.1, AREA1: 0.1, AREA2: 100, DIST: 1,
+		QUADS: 1, Q_PTS: 3, N_PTS: 3, K_PTS: 1, A_PTS: 1, B_PTS: 1, C_PTS: 1, D_PTS: 1, E_PTS: 1, F_PTS: 1, G_PTS: 1,
+	}
+	d := decide.Decide{}
+	if err := d.Decide(input); err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := SVG(&b, input, d, Options{}); err != nil {
+		t.Error(err)
+		return
+	}
+	svg := b.String()
+	for _, overlay := range []string{"&gt; LENGTH1", "RADIUS1 1", "EPSILON 0.1", "area 6 &gt; AREA1", "area 0.5 &gt; AREA1", "more than 1 quadrants", "x decreases", "DIST 1", "&lt; AREA2", "RADIUS2 10"} {
+		if !strings.Contains(svg, overlay) {
+			t.Error("Expected the overlay", overlay, "in", svg)
+			return
+		}
+	}
+	if err := wellFormed(b.Bytes()); err != nil {
+		t.Error(err)
+		return
+	}
+}
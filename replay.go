@@ -0,0 +1,72 @@
+package main
+
+import (
+	"github.com/tdurieux/go-decide/decide"
+	"github.com/tdurieux/go-decide/replay"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+func replayCmd(args []string) int {
+	flags := newFlagSet("replay", "log | directory",
+		"Re-evaluates the decisions of an audit log, or of a directory of\n" +
+		"results written by run -output, and reports those that changed.")
+	inputDir := flags.String("inputs", "", "the directory of the inputs of the results, when they moved since the decisions")
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitInput
+	}
+	source := flags.Arg(0)
+	fi, err := os.Stat(source)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInput
+	}
+	var records []replay.Record
+	if fi.IsDir() {
+		records, err = replay.FromDir(source, *inputDir)
+	} else {
+		records, err = replay.FromLog(source)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInput
+	}
+
+	report := replay.Run(context.Background(), &decide.Engine{}, records)
+	if *asJSON {
+		content, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitInternal
+		}
+		fmt.Println(string(content))
+	} else {
+		for _, change := range report.Changes {
+			if change.Error != "" {
+				fmt.Printf("%s: ERROR %s\n", change.Source, change.Error)
+				continue
+			}
+			fmt.Printf("%s: LICs %v changed\n", change.Source, change.LICs)
+			for _, d := range change.Differences {
+				fmt.Println("\t" + d.String())
+			}
+		}
+		fmt.Printf("%d decisions replayed, %d changed, %d LAUNCH changed\n", report.Records, len(report.Changes), report.Launch)
+		for lic, n := range report.LICs {
+			if n != 0 {
+				fmt.Printf("LIC %d: %d changed\n", lic, n)
+			}
+		}
+	}
+	if !report.Identical() {
+		return exitNo
+	}
+	return exitOK
+}
@@ -0,0 +1,192 @@
+// Package replay re-evaluates recorded decisions with the current engine
+// and reports those that changed.
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/tdurieux/go-decide/audit"
+	"github.com/tdurieux/go-decide/decide"
+)
+
+// Record is a recorded decision and the input it was made on.
+type Record struct {
+	// Source locates the record, e.g. a line of an audit log or a file.
+	Source string
+	Input  decide.INPUT
+	Result decide.Decide
+	// Err is set when the input of the record cannot be recovered.
+	Err error
+}
+
+// FromLog returns the records of the audit log at path. The log is not
+// verified, see audit.Verify.
+func FromLog(path string) ([]Record, error) {
+	entries, err := audit.Read(path)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]Record, len(entries))
+	for i, entry := range entries {
+		records[i] = Record{
+			Source: fmt.Sprintf("%s:%d", path, i + 1),
+			Input:  entry.Input,
+			Result: entry.Result,
+		}
+	}
+	return records, nil
+}
+
+// FromDir returns the records of the result files of dir, written by
+// run -output. The input of each result is read from the path in its
+// provenance, relative to inputDir when it is set, and must still match
+// the recorded input hash.
+func FromDir(dir string, inputDir string) ([]Record, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, f.Name())
+		record := Record{Source: path}
+		record.Result, record.Input, record.Err = readResult(path, inputDir)
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func readResult(path string, inputDir string) (decide.Decide, decide.INPUT, error) {
+	var result decide.Decide
+	var input decide.INPUT
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return result, input, err
+	}
+	if err := json.Unmarshal(content, &result); err != nil {
+		return result, input, err
+	}
+	p := result.Provenance
+	if p == nil {
+		return result, input, fmt.Errorf("no provenance")
+	}
+	if p.Input == "" || p.Input == "-" {
+		return result, input, fmt.Errorf("the input was not read from a file")
+	}
+	inputPath := p.Input
+	if inputDir != "" {
+		inputPath = filepath.Join(inputDir, filepath.Base(p.Input))
+	}
+	content, err = ioutil.ReadFile(inputPath)
+	if err != nil {
+		return result, input, err
+	}
+	if err := json.Unmarshal(content, &input); err != nil {
+		return result, input, fmt.Errorf("%s: %s", inputPath, err)
+	}
+	hash, err := decide.Hash(input)
+	if err != nil {
+		return result, input, err
+	}
+	if hash != p.InputSHA256 {
+		return result, input, fmt.Errorf("%s changed since the decision", inputPath)
+	}
+	return result, input, nil
+}
+
+// Change is a record whose decision is not reproduced.
+type Change struct {
+	Source string `json:"SOURCE"`
+	// LICs lists the LICs whose CMV, PUM row or FUV entry changed.
+	LICs        []int               `json:"LICS"`
+	Differences []decide.Difference `json:"DIFFERENCES,omitempty"`
+	// Error is set when the record could not be replayed.
+	Error string `json:"ERROR,omitempty"`
+}
+
+// Report summarizes a replay.
+type Report struct {
+	Records int      `json:"RECORDS"`
+	Changes []Change `json:"CHANGES"`
+	// LICs counts, for each LIC, the records where it changed.
+	LICs [decide.NB_LIC]int `json:"LICS"`
+	// Launch counts the records whose LAUNCH decision changed.
+	Launch int `json:"LAUNCH"`
+}
+
+// Identical reports whether every record was reproduced.
+func (r Report) Identical() bool {
+	return len(r.Changes) == 0
+}
+
+// Engine returns the engine that replays record: an engine like the one
+// that recorded it, according to its provenance, with the observer and
+// the logger of engine. Without provenance, it is engine.
+func (record Record) Engine(engine *decide.Engine) *decide.Engine {
+	p := record.Result.Provenance
+	if p == nil {
+		return engine
+	}
+	e := p.Engine()
+	e.Observer = engine.Observer
+	e.Logger = engine.Logger
+	return e
+}
+
+// Run re-evaluates records with the engines that recorded them, see
+// Record.Engine, and compares the new decisions with the recorded ones.
+func Run(ctx context.Context, engine *decide.Engine, records []Record) Report {
+	report := Report{Records: len(records), Changes: []Change{}}
+	for _, record := range records {
+		if record.Err != nil {
+			report.Changes = append(report.Changes, Change{Source: record.Source, Error: record.Err.Error()})
+			continue
+		}
+		result, err := record.Engine(engine).Decide(ctx, record.Input)
+		if err != nil {
+			report.Changes = append(report.Changes, Change{Source: record.Source, Error: err.Error()})
+			continue
+		}
+		diffs := decide.Compare(record.Result, result)
+		if diffs == nil {
+			continue
+		}
+		change := Change{Source: record.Source, LICs: changedLICs(record.Result, result), Differences: diffs}
+		for _, lic := range change.LICs {
+			report.LICs[lic]++
+		}
+		if record.Result.Launch != result.Launch {
+			report.Launch++
+		}
+		report.Changes = append(report.Changes, change)
+	}
+	return report
+}
+
+// changedLICs lists the LICs whose CMV, PUM row or FUV entry differ
+// between a and b, ignoring the CMV and PUM entries of the LICs that a
+// lazy evaluation did not evaluate.
+func changedLICs(a decide.Decide, b decide.Decide) []int {
+	evaluated := func(lic int) bool {
+		return a.Evaluated(lic) && b.Evaluated(lic)
+	}
+	var lics []int
+	for i := 0; i < decide.NB_LIC; i++ {
+		changed := a.FUV[i] != b.FUV[i]
+		for j := 0; j < decide.NB_LIC && evaluated(i); j++ {
+			changed = changed || a.CMV[i] != b.CMV[i] || evaluated(j) && a.PUM[i][j] != b.PUM[i][j]
+		}
+		if changed {
+			lics = append(lics, i)
+		}
+	}
+	return lics
+}
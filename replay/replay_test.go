@@ -0,0 +1,170 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tdurieux/go-decide/audit"
+	"github.com/tdurieux/go-decide/decide"
+)
+
+func decision(t *testing.T, input decide.INPUT, inputPath string) decide.Decide {
+	d := decide.Decide{}
+	if err := d.Decide(input); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetProvenance(inputPath, time.Unix(0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestReplayLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	log, err := audit.Open(path, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for seed := int64(1); seed <= 3; seed++ {
+		input := decide.Generate(seed)
+		d := decision(t, input, "-")
+		if seed == 2 {
+			// A decision recorded by an engine that disagrees on LIC 4.
+			d.CMV[4] = !d.CMV[4]
+		}
+		if _, err := log.Append(input, d, time.Unix(0, 0)); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	records, err := FromLog(path)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	report := Run(context.Background(), &decide.Engine{}, records)
+	if report.Records != 3 || len(report.Changes) != 1 {
+		t.Error("Expected 1 change out of 3 records, got", report)
+		return
+	}
+	change := report.Changes[0]
+	if change.Source != path + ":2" || len(change.LICs) == 0 || change.LICs[0] != 4 {
+		t.Error("Expected LIC 4 of the second entry to change, got", change)
+		return
+	}
+	if report.LICs[4] != 1 {
+		t.Error("Expected LIC 4 to be counted, got", report.LICs)
+		return
+	}
+}
+
+func TestReplayDir(t *testing.T) {
+	inputDir := t.TempDir()
+	outputDir := t.TempDir()
+	for _, name := range []string{"a.json", "b.json"} {
+		input := decide.Generate(int64(len(name) + int(name[0])))
+		content, err := json.Marshal(input)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		inputPath := filepath.Join(inputDir, name)
+		if err := ioutil.WriteFile(inputPath, content, 0644); err != nil {
+			t.Error(err)
+			return
+		}
+		content, err = json.Marshal(decision(t, input, inputPath))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if err := ioutil.WriteFile(filepath.Join(outputDir, name), content, 0644); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	records, err := FromDir(outputDir, "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	report := Run(context.Background(), &decide.Engine{}, records)
+	if report.Records != 2 || !report.Identical() {
+		t.Error("Expected the decisions to be reproduced, got", report)
+		return
+	}
+
+	// The input changed after the decision.
+	if err := ioutil.WriteFile(filepath.Join(inputDir, "b.json"), []byte("{}"), 0644); err != nil {
+		t.Error(err)
+		return
+	}
+	records, err = FromDir(outputDir, "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	report = Run(context.Background(), &decide.Engine{}, records)
+	if len(report.Changes) != 1 || report.Changes[0].Error == "" {
+		t.Error("Expected the changed input to be reported, got", report)
+		return
+	}
+}
+
+func TestReplayEngine(t *testing.T) {
+	// A decision of an engine that accepts more points than the
+	// specification.
+	input := decide.Generate(1)
+	for len(input.Points) < 150 {
+		input.Points = append(input.Points, input.Points[len(input.Points) % 25])
+	}
+	input.NumPoints = len(input.Points)
+	engine := &decide.Engine{MaxPoints: 200}
+	d, err := engine.Decide(context.Background(), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetProvenance("-", time.Unix(0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	records := []Record{{Source: "large", Input: input, Result: d}}
+	report := Run(context.Background(), &decide.Engine{}, records)
+	if !report.Identical() {
+		t.Error("Expected the decision to be replayed with the limits of its engine, got", report.Changes)
+		return
+	}
+}
+
+func TestReplayEvaluation(t *testing.T) {
+	engine := &decide.Engine{Evaluation: decide.EvaluationStrict}
+	var records []Record
+	changed := false
+	for seed := int64(0); seed < 20; seed++ {
+		input := decide.Generate(seed)
+		d, err := engine.Decide(context.Background(), input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := d.SetProvenance("-", time.Unix(0, 0)); err != nil {
+			t.Fatal(err)
+		}
+		float := decision(t, input, "-")
+		changed = changed || decide.Compare(d, float) != nil
+		records = append(records, Record{Source: "strict", Input: input, Result: d})
+	}
+	if !changed {
+		t.Fatal("Expected a strict decision that differs from the float one")
+	}
+	report := Run(context.Background(), &decide.Engine{}, records)
+	if !report.Identical() {
+		t.Error("Expected the strict decisions to be replayed with the strict evaluation, got", report.Changes)
+		return
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"github.com/tdurieux/go-decide/decide"
+	"github.com/tdurieux/go-decide/report"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+func reportCmd(args []string) int {
+	flags := newFlagSet("report", "input file or directory",
+		"Writes a self-contained HTML report of the decision of an input: its\n" +
+		"parameters, its points with the witnesses of the LICs met, its CMV,\n" +
+		"LCM, PUM and FUV, and LAUNCH with its explanation. For a directory, a\n" +
+		"report is written for each input with an index page that filters\n" +
+		"them by outcome. The reports need no network access.")
+	output := flags.String("o", "", "the HTML file to write for an input, stdout when empty; the directory to write for a directory of inputs, report when empty")
+	title := flags.String("title", "Decisions", "the title of the index page")
+	evaluation := evaluationFlag(flags)
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitInput
+	}
+	source := flags.Arg(0)
+	fi, err := os.Stat(source)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInput
+	}
+	paths, err := inputFiles(source)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInput
+	}
+
+	engine := &decide.Engine{Evaluation: *evaluation}
+	code := exitOK
+	pages := make([]report.Page, len(paths))
+	for i, inputPath := range paths {
+		page := report.Page{Name: strings.TrimSuffix(path.Base(inputPath), ".json")}
+		page.Input, page.Err = getInput(inputPath)
+		if page.Err == nil {
+			page.Decision, page.Err = engine.Decide(context.Background(), page.Input)
+		}
+		if page.Err != nil {
+			fmt.Fprintln(os.Stderr, inputPath, page.Err)
+			code = max(code, exitCode(page.Err))
+		}
+		pages[i] = page
+	}
+
+	if !fi.IsDir() {
+		err = writeReport(*output, func(w io.Writer) error { return report.Write(w, pages[0]) })
+	} else {
+		dir := *output
+		if dir == "" {
+			dir = "report"
+		}
+		err = os.MkdirAll(dir, 0755)
+		for _, page := range pages {
+			if err != nil {
+				break
+			}
+			page := page
+			err = writeReport(path.Join(dir, page.Name + ".html"), func(w io.Writer) error { return report.Write(w, page) })
+		}
+		if err == nil {
+			err = writeReport(path.Join(dir, "index.html"), func(w io.Writer) error { return report.WriteIndex(w, *title, pages) })
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInternal
+	}
+	return code
+}
+
+// writeReport writes a report to the file at filePath, or to stdout when
+// filePath is empty.
+func writeReport(filePath string, write func(w io.Writer) error) error {
+	if filePath == "" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
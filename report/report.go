@@ -0,0 +1,311 @@
+// Package report writes self-contained HTML reports of decisions, which
+// can be opened offline: the style and the drawings are embedded in the
+// pages.
+package report
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"reflect"
+	"strconv"
+
+	"github.com/tdurieux/go-decide/decide"
+	"github.com/tdurieux/go-decide/render"
+)
+
+//go:embed style.css
+var style string
+
+// Outcomes of a page, used as the classes of the index filters.
+const (
+	OutcomeYes   = "yes"
+	OutcomeNo    = "no"
+	OutcomeError = "error"
+)
+
+// Page is the report of an input.
+type Page struct {
+	// Name identifies the input, e.g. its file name without extension.
+	// The page of an index is Name + ".html".
+	Name     string
+	Input    decide.INPUT
+	Decision decide.Decide
+	// Err is set when the input could not be decided.
+	Err error
+}
+
+// Outcome returns the outcome of the page: OutcomeYes, OutcomeNo or
+// OutcomeError.
+func (p Page) Outcome() string {
+	if p.Err != nil {
+		return OutcomeError
+	}
+	if p.Decision.Launch == "YES" {
+		return OutcomeYes
+	}
+	return OutcomeNo
+}
+
+const tplPage = `<!DOCTYPE html>
+<html>
+	<head>
+		<meta charset="utf-8">
+		<meta name="viewport" content="width=device-width, initial-scale=1">
+		<title>Decide - {{ .Name }}</title>
+		<style>{{ .Style }}</style>
+	</head>
+	<body>{{ if .Err }}
+		<h1>{{ .Name }} - <span class="error">ERROR</span></h1>
+		<p class="explanation">{{ .Err }}</p>{{ else }}
+		<h1>{{ .Name }} - <span class="{{ .Outcome }}">LAUNCH {{ .Decision.Launch }}</span></h1>
+		<p class="explanation">{{ .Explanation }}</p>
+
+		<h2>Parameters</h2>
+		<table>
+			<thead>
+				<tr><th class="name">Parameter</th><th>Value</th></tr>
+			</thead>
+			<tbody>
+				<tr><td class="name">NUMPOINTS</td><td>{{ .Input.NumPoints }}</td></tr>{{ range .Parameters }}
+				<tr><td class="name">{{ .Name }}</td><td>{{ .Value }}</td></tr>{{ end }}
+			</tbody>
+		</table>
+
+		<h2>Points</h2>
+		{{ .Plot }}
+
+		<h2>CMV</h2>
+		<table>
+			<thead>
+				<tr><th>LIC</th><th>CMV</th><th class="name">Witness</th></tr>
+			</thead>
+			<tbody>{{ range .CMV }}
+				<tr>
+					<td><span class="swatch" style="background: {{ .Color }}"></span>{{ .LIC }}</td>{{ if .Skipped }}
+					<td class="skipped">not evaluated</td>{{ else }}
+					<td class="{{ if .Value }}yes{{ else }}no{{ end }}">{{ .Value }}</td>{{ end }}
+					<td class="name">{{ .Witness }}</td>
+				</tr>{{ end }}
+			</tbody>
+		</table>
+
+		<h2>LCM</h2>
+		<p class="legend"><span class="andd">ANDD: CMV[i] and CMV[j]</span><span class="orr">{{ .Orr }}: CMV[i] or CMV[j]</span><span class="notused">{{ .NotUsed }}: not used</span></p>
+		<table class="grid">
+			<thead>
+				<tr><th></th>{{ range $j, $e := .Decision.PUM }}<th>{{ $j }}</th>{{ end }}</tr>
+			</thead>
+			<tbody>{{ range $i, $row := .LCM }}
+				<tr>
+					<th>{{ $i }}</th>{{ range $row }}
+					<td class="{{ .Class }}">{{ .Command }}</td>{{ end }}
+				</tr>{{ end }}
+			</tbody>
+		</table>
+
+		<h2>PUM</h2>
+		<table class="grid">
+			<thead>
+				<tr><th></th>{{ range $j, $e := .Decision.PUM }}<th>{{ $j }}</th>{{ end }}</tr>
+			</thead>
+			<tbody>{{ range $i, $row := .Decision.PUM }}
+				<tr>
+					<th>{{ $i }}</th>{{ range $j, $v := $row }}
+					<td class="{{ if eq $i $j }}diagonal{{ else if $v }}yes{{ else }}no{{ end }}">{{ if $v }}V{{ else }}X{{ end }}</td>{{ end }}
+				</tr>{{ end }}
+			</tbody>
+		</table>
+
+		<h2>PUV and FUV</h2>
+		<table class="grid">
+			<thead>
+				<tr><th></th>{{ range $i, $e := .Decision.FUV }}<th>{{ $i }}</th>{{ end }}</tr>
+			</thead>
+			<tbody>
+				<tr>
+					<th>PUV</th>{{ range .Input.PUV }}
+					<td class="{{ if . }}andd{{ else }}notused{{ end }}">{{ if . }}V{{ else }}X{{ end }}</td>{{ end }}
+				</tr>
+				<tr>
+					<th>FUV</th>{{ range .Decision.FUV }}
+					<td class="{{ if . }}yes{{ else }}no{{ end }}">{{ if . }}V{{ else }}X{{ end }}</td>{{ end }}
+				</tr>
+			</tbody>
+		</table>{{ end }}
+	</body>
+</html>
+`
+
+const tplIndex = `<!DOCTYPE html>
+<html>
+	<head>
+		<meta charset="utf-8">
+		<meta name="viewport" content="width=device-width, initial-scale=1">
+		<title>Decide - {{ .Title }}</title>
+		<style>{{ .Style }}</style>
+	</head>
+	<body>
+		<h1>{{ .Title }}</h1>
+		<div class="filters">
+			<input type="radio" name="outcome" id="show-all" checked><label for="show-all">All ({{ len .Pages }})</label>
+			<input type="radio" name="outcome" id="show-yes"><label for="show-yes" class="yes">YES ({{ index .Counts "yes" }})</label>
+			<input type="radio" name="outcome" id="show-no"><label for="show-no" class="no">NO ({{ index .Counts "no" }})</label>
+			<input type="radio" name="outcome" id="show-error"><label for="show-error" class="error">ERROR ({{ index .Counts "error" }})</label>
+			<table>
+				<thead>
+					<tr><th class="name">Input</th><th>LAUNCH</th><th>NUMPOINTS</th><th>LICs met</th><th>FUV false</th></tr>
+				</thead>
+				<tbody>{{ range .Pages }}
+					<tr class="{{ .Outcome }}">
+						<td class="name"><a href="{{ .Name }}.html">{{ .Name }}</a></td>{{ if .Err }}
+						<td>ERROR</td><td></td><td></td><td></td>{{ else }}
+						<td>{{ .Decision.Launch }}</td>
+						<td>{{ .Input.NumPoints }}</td>
+						<td>{{ count .Decision.CMV true }}</td>
+						<td>{{ count .Decision.FUV false }}</td>{{ end }}
+					</tr>{{ end }}
+				</tbody>
+			</table>
+		</div>
+	</body>
+</html>
+`
+
+var funcs = template.FuncMap{
+	"count": func(values [decide.NB_LIC]bool, value bool) int {
+		n := 0
+		for _, v := range values {
+			if v == value {
+				n++
+			}
+		}
+		return n
+	},
+}
+
+var (
+	pageTemplate  = template.Must(template.New("page").Parse(tplPage))
+	indexTemplate = template.Must(template.New("index").Funcs(funcs).Parse(tplIndex))
+)
+
+type parameter struct {
+	Name  string
+	Value interface{}
+}
+
+type cmvEntry struct {
+	LIC     int
+	Value   bool
+	Skipped bool
+	Color   template.CSS
+	Witness string
+}
+
+type lcmCell struct {
+	Command decide.Command
+	Class   string
+}
+
+// parameters lists the fields of p in the order of their declaration.
+func parameters(p decide.Parameters) []parameter {
+	v := reflect.ValueOf(p)
+	params := make([]parameter, v.NumField())
+	for i := range params {
+		params[i] = parameter{v.Type().Field(i).Name, v.Field(i).Interface()}
+	}
+	return params
+}
+
+func indices(points []int) string {
+	s := ""
+	for i, p := range points {
+		if i > 0 {
+			s += ", "
+		}
+		s += strconv.Itoa(p)
+	}
+	return s
+}
+
+// Write writes the report of page to w: its parameters, its points with
+// the witnesses of the LICs met, its CMV, LCM, PUM, PUV and FUV, and its
+// LAUNCH decision with the causal chain of a NO.
+func Write(w io.Writer, page Page) error {
+	data := struct {
+		Page
+		Style       template.CSS
+		Explanation string
+		Parameters  []parameter
+		Plot        template.HTML
+		CMV         []cmvEntry
+		LCM         [decide.NB_LIC][decide.NB_LIC]lcmCell
+		Orr         decide.Command
+		NotUsed     decide.Command
+	}{Page: page, Style: template.CSS(style), Orr: decide.ORR, NotUsed: decide.NOTUSED}
+
+	if page.Err == nil {
+		d := page.Decision
+		data.Explanation = d.Explain().String()
+		data.Parameters = parameters(page.Input.Parameters)
+
+		var plot bytes.Buffer
+		if err := render.SVG(&plot, page.Input, d, render.Options{}); err != nil {
+			return err
+		}
+		data.Plot = template.HTML(plot.String())
+
+		witnesses := make(map[int]decide.Witness)
+		for _, witness := range d.Witnesses() {
+			witnesses[witness.LIC] = witness
+		}
+		skipped := make(map[int]bool)
+		for _, lic := range d.NotEvaluated {
+			skipped[lic] = true
+		}
+		for lic, value := range d.CMV {
+			entry := cmvEntry{LIC: lic, Value: value, Skipped: skipped[lic], Color: template.CSS(render.Color(lic))}
+			if witness, ok := witnesses[lic]; ok {
+				entry.Witness = "points " + indices(witness.Points)
+				if witness.Second != nil {
+					entry.Witness += fmt.Sprintf(" (second condition: points %s)", indices(witness.Second))
+				}
+			}
+			data.CMV = append(data.CMV, entry)
+		}
+
+		for i := range data.LCM {
+			row := page.Input.LCM[strconv.Itoa(i)]
+			for j, c := range row {
+				class := ""
+				switch c {
+				case decide.ANDD:
+					class = "andd"
+				case decide.ORR:
+					class = "orr"
+				case decide.NOTUSED:
+					class = "notused"
+				}
+				data.LCM[i][j] = lcmCell{c, class}
+			}
+		}
+	}
+	return pageTemplate.Execute(w, data)
+}
+
+// WriteIndex writes to w an index of pages, with a link to the page of
+// each input and filters by outcome.
+func WriteIndex(w io.Writer, title string, pages []Page) error {
+	counts := map[string]int{OutcomeYes: 0, OutcomeNo: 0, OutcomeError: 0}
+	for _, page := range pages {
+		counts[page.Outcome()]++
+	}
+	return indexTemplate.Execute(w, struct {
+		Title  string
+		Style  template.CSS
+		Pages  []Page
+		Counts map[string]int
+	}{title, template.CSS(style), pages, counts})
+}
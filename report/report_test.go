@@ -0,0 +1,112 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tdurieux/go-decide/decide"
+)
+
+func page(t *testing.T, name string, input decide.INPUT) Page {
+	d := decide.Decide{}
+	err := d.Decide(input)
+	return Page{Name: name, Input: input, Decision: d, Err: err}
+}
+
+// selfContained checks that html references no external resource and
+// that html/template did not reject any value.
+func selfContained(html string) error {
+	for _, forbidden := range []string{"ZgotmplZ", "<link", "src=", "http://", "https://"} {
+		if strings.Contains(strings.Replace(html, `xmlns="http://www.w3.org/2000/svg"`, "", -1), forbidden) {
+			return errors.New("unexpected " + forbidden)
+		}
+	}
+	return nil
+}
+
+func TestWrite(t *testing.T) {
+	p := page(t, "input1", decide.Generate(1))
+	if p.Err != nil {
+		t.Fatal(p.Err)
+	}
+	var b bytes.Buffer
+	if err := Write(&b, p); err != nil {
+		t.Error(err)
+		return
+	}
+	html := b.String()
+	if err := selfContained(html); err != nil {
+		t.Error("Expected a self-contained page:", err)
+		return
+	}
+	cells := strings.Count(html, `<td class="andd">`) + strings.Count(html, `<td class="orr">`) + strings.Count(html, `<td class="notused">`)
+	// The LCM grid and the PUV row.
+	if cells != decide.NB_LIC * decide.NB_LIC + decide.NB_LIC {
+		t.Error("Expected a colour-coded LCM grid of 15x15 cells, got", cells)
+		return
+	}
+	pum := strings.Count(html, `<td class="diagonal">`)
+	if pum != decide.NB_LIC {
+		t.Error("Expected the diagonal of the PUM to be marked, got", pum)
+		return
+	}
+	for _, expected := range []string{"<svg", "LAUNCH " + p.Decision.Launch, "<td class=\"name\">EPSILON</td>", "<style>", p.Decision.Explain().Reasons[0].String()} {
+		if !strings.Contains(html, expected) {
+			t.Error("Expected the page to contain", expected)
+			return
+		}
+	}
+	for _, w := range p.Decision.Witnesses() {
+		if !strings.Contains(html, "points " + indices(w.Points)) {
+			t.Error("Expected the witness of LIC", w.LIC, "in the CMV table")
+			return
+		}
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	p := page(t, "invalid", decide.INPUT{NumPoints: 1})
+	if p.Err == nil || p.Outcome() != OutcomeError {
+		t.Fatal("Expected the input to be invalid")
+	}
+	var b bytes.Buffer
+	if err := Write(&b, p); err != nil {
+		t.Error(err)
+		return
+	}
+	if !strings.Contains(b.String(), "ERROR") || !strings.Contains(b.String(), "NumPoints") || strings.Contains(b.String(), "<svg") {
+		t.Error("Expected the page to report the error, got", b.String())
+		return
+	}
+}
+
+func TestWriteIndex(t *testing.T) {
+	var pages []Page
+	for seed := int64(0); seed < 10; seed++ {
+		pages = append(pages, page(t, "generated" + strconv.FormatInt(seed, 10), decide.Generate(seed)))
+	}
+	pages = append(pages, page(t, "invalid", decide.INPUT{NumPoints: 1}))
+	var b bytes.Buffer
+	if err := WriteIndex(&b, "Decisions", pages); err != nil {
+		t.Error(err)
+		return
+	}
+	html := b.String()
+	if err := selfContained(html); err != nil {
+		t.Error("Expected a self-contained index:", err)
+		return
+	}
+	yes := strings.Count(html, `<tr class="yes">`)
+	no := strings.Count(html, `<tr class="no">`)
+	if yes + no != 10 || strings.Count(html, `<tr class="error">`) != 1 || !strings.Contains(html, "ERROR (1)") {
+		t.Error("Expected a row by page with its outcome, got", html)
+		return
+	}
+	if !strings.Contains(html, `href="invalid.html"`) || !strings.Contains(html, `id="show-no"`) {
+		t.Error("Expected links to the pages and filters by outcome")
+		return
+	}
+}
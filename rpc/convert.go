@@ -0,0 +1,221 @@
+package rpc
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/tdurieux/go-decide/decide"
+	"github.com/tdurieux/go-decide/decidepb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// commandToProto maps a connector by its JSON name, so that an input
+// has the same meaning in JSON and in protobuf.
+func commandToProto(c decide.Command) (decidepb.Command, error) {
+	v, ok := decidepb.Command_value[string(c)]
+	if !ok || v == 0 {
+		return 0, fmt.Errorf("invalid command %q", c)
+	}
+	return decidepb.Command(v), nil
+}
+
+func commandFromProto(c decidepb.Command) decide.Command {
+	if c == decidepb.Command_COMMAND_UNSPECIFIED {
+		return ""
+	}
+	return decide.Command(c.String())
+}
+
+// InputToProto converts an INPUT to its protobuf message.
+func InputToProto(input decide.INPUT) (*decidepb.Input, error) {
+	m := &decidepb.Input{
+		NumPoints:  int32(input.NumPoints),
+		Points:     make([]*decidepb.Point, len(input.Points)),
+		Puv:        append([]bool(nil), input.PUV[:]...),
+		RandomSeed: input.RandomSeed,
+		Parameters: parametersToProto(input.Parameters),
+	}
+	for i, p := range input.Points {
+		m.Points[i] = &decidepb.Point{X: p[0], Y: p[1]}
+	}
+	if input.LCM != nil {
+		m.Lcm = make([]*decidepb.LcmRow, decide.NB_LIC)
+		for i := range m.Lcm {
+			row, ok := input.LCM[strconv.Itoa(i)]
+			if !ok {
+				return nil, fmt.Errorf("missing LCM row %d", i)
+			}
+			m.Lcm[i] = &decidepb.LcmRow{Commands: make([]decidepb.Command, decide.NB_LIC)}
+			for j, c := range row {
+				command, err := commandToProto(c)
+				if err != nil {
+					return nil, err
+				}
+				m.Lcm[i].Commands[j] = command
+			}
+		}
+	}
+	return m, nil
+}
+
+// InputFromProto converts a protobuf message to an INPUT. Repeated
+// fields of the wrong length are rejected.
+func InputFromProto(m *decidepb.Input) (decide.INPUT, error) {
+	var input decide.INPUT
+	if m == nil {
+		return input, fmt.Errorf("missing input")
+	}
+	input.NumPoints = int(m.NumPoints)
+	input.Points = make([][2]float64, len(m.Points))
+	for i, p := range m.Points {
+		input.Points[i] = [2]float64{p.GetX(), p.GetY()}
+	}
+	if len(m.Puv) != decide.NB_LIC {
+		return input, fmt.Errorf("expected %d PUV values, got %d", decide.NB_LIC, len(m.Puv))
+	}
+	copy(input.PUV[:], m.Puv)
+	if len(m.Lcm) != decide.NB_LIC {
+		return input, fmt.Errorf("expected %d LCM rows, got %d", decide.NB_LIC, len(m.Lcm))
+	}
+	input.LCM = make(map[string][decide.NB_LIC]decide.Command, decide.NB_LIC)
+	for i, row := range m.Lcm {
+		if len(row.GetCommands()) != decide.NB_LIC {
+			return input, fmt.Errorf("expected %d commands in LCM row %d, got %d", decide.NB_LIC, i, len(row.GetCommands()))
+		}
+		var commands [decide.NB_LIC]decide.Command
+		for j, c := range row.Commands {
+			commands[j] = commandFromProto(c)
+		}
+		input.LCM[strconv.Itoa(i)] = commands
+	}
+	input.Parameters = parametersFromProto(m.Parameters)
+	input.RandomSeed = m.RandomSeed
+	return input, nil
+}
+
+func parametersToProto(p decide.Parameters) *decidepb.Parameters {
+	return &decidepb.Parameters{
+		Radius1: p.RADIUS1,
+		Radius2: p.RADIUS2,
+		Length1: p.LENGTH1,
+		Length2: p.LENGTH2,
+		Dist:    p.DIST,
+		Epsilon: p.EPSILON,
+		Quads:   int32(p.QUADS),
+		Area1:   p.AREA1,
+		Area2:   p.AREA2,
+		APts:    int32(p.A_PTS),
+		BPts:    int32(p.B_PTS),
+		CPts:    int32(p.C_PTS),
+		DPts:    int32(p.D_PTS),
+		EPts:    int32(p.E_PTS),
+		FPts:    int32(p.F_PTS),
+		GPts:    int32(p.G_PTS),
+		KPts:    int32(p.K_PTS),
+		NPts:    int32(p.N_PTS),
+		QPts:    int32(p.Q_PTS),
+	}
+}
+
+func parametersFromProto(m *decidepb.Parameters) decide.Parameters {
+	return decide.Parameters{
+		RADIUS1: m.GetRadius1(),
+		RADIUS2: m.GetRadius2(),
+		LENGTH1: m.GetLength1(),
+		LENGTH2: m.GetLength2(),
+		DIST:    m.GetDist(),
+		EPSILON: m.GetEpsilon(),
+		QUADS:   int(m.GetQuads()),
+		AREA1:   m.GetArea1(),
+		AREA2:   m.GetArea2(),
+		A_PTS:   int(m.GetAPts()),
+		B_PTS:   int(m.GetBPts()),
+		C_PTS:   int(m.GetCPts()),
+		D_PTS:   int(m.GetDPts()),
+		E_PTS:   int(m.GetEPts()),
+		F_PTS:   int(m.GetFPts()),
+		G_PTS:   int(m.GetGPts()),
+		K_PTS:   int(m.GetKPts()),
+		N_PTS:   int(m.GetNPts()),
+		Q_PTS:   int(m.GetQPts()),
+	}
+}
+
+// ResultToProto converts a result to its protobuf message.
+func ResultToProto(d decide.Decide) *decidepb.Result {
+	m := &decidepb.Result{
+		Launch: d.Launch,
+		Cmv:    append([]bool(nil), d.CMV[:]...),
+		Pum:    make([]*decidepb.PumRow, decide.NB_LIC),
+		Fuv:    append([]bool(nil), d.FUV[:]...),
+	}
+	for i := range m.Pum {
+		m.Pum[i] = &decidepb.PumRow{Values: append([]bool(nil), d.PUM[i][:]...)}
+	}
+	for _, lic := range d.NotEvaluated {
+		m.NotEvaluated = append(m.NotEvaluated, int32(lic))
+	}
+	if p := d.Provenance; p != nil {
+		m.Provenance = &decidepb.Provenance{
+			Input:         p.Input,
+			InputSha256:   p.InputSHA256,
+			RandomSeed:    p.RandomSeed,
+			EngineVersion: p.EngineVersion,
+			Mode:          string(p.Mode),
+			Timestamp:     timestamppb.New(p.Timestamp),
+			Evaluation:    string(p.Evaluation),
+			MaxPoints:     int64(p.MaxPoints),
+			Lazy:          p.Lazy,
+		}
+		if b := p.Bounds; b != nil {
+			m.Provenance.Bounds = &decidepb.Bounds{
+				Min: &decidepb.Point{X: b.Min[0], Y: b.Min[1]},
+				Max: &decidepb.Point{X: b.Max[0], Y: b.Max[1]},
+			}
+		}
+	}
+	return m
+}
+
+// ResultFromProto converts a protobuf message to a result.
+func ResultFromProto(m *decidepb.Result) (decide.Decide, error) {
+	var d decide.Decide
+	if len(m.GetCmv()) != decide.NB_LIC || len(m.GetFuv()) != decide.NB_LIC || len(m.GetPum()) != decide.NB_LIC {
+		return d, fmt.Errorf("expected %d CMV, FUV and PUM entries", decide.NB_LIC)
+	}
+	d.Launch = m.Launch
+	copy(d.CMV[:], m.Cmv)
+	copy(d.FUV[:], m.Fuv)
+	for i, row := range m.Pum {
+		if len(row.GetValues()) != decide.NB_LIC {
+			return d, fmt.Errorf("expected %d values in PUM row %d", decide.NB_LIC, i)
+		}
+		copy(d.PUM[i][:], row.Values)
+	}
+	for _, lic := range m.NotEvaluated {
+		if lic < 0 || lic >= decide.NB_LIC {
+			return d, fmt.Errorf("invalid LIC %d not evaluated", lic)
+		}
+		d.NotEvaluated = append(d.NotEvaluated, int(lic))
+	}
+	if p := m.Provenance; p != nil {
+		d.Provenance = &decide.Provenance{
+			Input:         p.Input,
+			InputSHA256:   p.InputSha256,
+			RandomSeed:    p.RandomSeed,
+			EngineVersion: p.EngineVersion,
+			Mode:          decide.Mode(p.Mode),
+			Timestamp:     p.Timestamp.AsTime(),
+			Evaluation:    decide.Evaluation(p.Evaluation),
+			MaxPoints:     int(p.MaxPoints),
+			Lazy:          p.Lazy,
+		}
+		if b := p.Bounds; b != nil {
+			d.Provenance.Bounds = &decide.Bounds{
+				Min: [2]float64{b.GetMin().GetX(), b.GetMin().GetY()},
+				Max: [2]float64{b.GetMax().GetX(), b.GetMax().GetY()},
+			}
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,128 @@
+// Package rpc exposes the decision engine as a gRPC service, see
+// proto/decide.proto.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/tdurieux/go-decide/audit"
+	"github.com/tdurieux/go-decide/decide"
+	"github.com/tdurieux/go-decide/decidepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements decidepb.DecisionServiceServer.
+type Server struct {
+	decidepb.UnimplementedDecisionServiceServer
+	// Clock returns the timestamp recorded in the provenance of results.
+	Clock func() time.Time
+	// Engine evaluates the inputs.
+	Engine *decide.Engine
+	// Audit, when set, records every decision.
+	Audit *audit.Log
+}
+
+// NewServer returns a server recording the current time in results.
+func NewServer() *Server {
+	return &Server{Clock: time.Now, Engine: &decide.Engine{}}
+}
+
+// Register registers s on g.
+func Register(g *grpc.Server, s *Server) {
+	decidepb.RegisterDecisionServiceServer(g, s)
+}
+
+// Evaluate decides a single input. Invalid inputs fail with
+// codes.InvalidArgument, an expired deadline with codes.DeadlineExceeded.
+func (s *Server) Evaluate(ctx context.Context, req *decidepb.EvaluateRequest) (*decidepb.EvaluateResponse, error) {
+	result, err := s.evaluate(ctx, req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &decidepb.EvaluateResponse{
+		Id:      req.GetId(),
+		Outcome: &decidepb.EvaluateResponse_Result{Result: result},
+	}, nil
+}
+
+// EvaluateStream decides the inputs of the stream in order. An invalid
+// input gives an error response and the stream goes on.
+func (s *Server) EvaluateStream(stream decidepb.DecisionService_EvaluateStreamServer) error {
+	ctx := stream.Context()
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		response := &decidepb.EvaluateResponse{Id: req.GetId()}
+		result, err := s.evaluate(ctx, req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return toStatus(ctx.Err())
+			}
+			response.Outcome = &decidepb.EvaluateResponse_Error{Error: toProtoError(err)}
+		} else {
+			response.Outcome = &decidepb.EvaluateResponse_Result{Result: result}
+		}
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+	}
+}
+
+// conversionError marks a message that cannot be converted to an INPUT.
+type conversionError struct {
+	error
+}
+
+func (s *Server) evaluate(ctx context.Context, req *decidepb.EvaluateRequest) (*decidepb.Result, error) {
+	input, err := InputFromProto(req.GetInput())
+	if err != nil {
+		return nil, conversionError{err}
+	}
+	decision, err := s.Engine.Decide(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := decision.SetProvenance("-", s.Clock()); err != nil {
+		return nil, err
+	}
+	if s.Audit != nil {
+		if _, err := s.Audit.Append(input, decision, s.Clock()); err != nil {
+			return nil, err
+		}
+	}
+	return ResultToProto(decision), nil
+}
+
+func toProtoError(err error) *decidepb.Error {
+	m := &decidepb.Error{Message: err.Error()}
+	var validation *decide.ValidationError
+	if errors.As(err, &validation) {
+		m.Field = validation.Field
+	}
+	return m
+}
+
+// toStatus maps err to its gRPC status.
+func toStatus(err error) error {
+	var validation *decide.ValidationError
+	var conversion conversionError
+	switch {
+	case errors.As(err, &validation), errors.As(err, &conversion):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,217 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"math"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tdurieux/go-decide/decide"
+	"github.com/tdurieux/go-decide/decidepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newClient starts an in-process server and returns a client connected to it.
+func newClient(t *testing.T) decidepb.DecisionServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	g := grpc.NewServer()
+	s := NewServer()
+	s.Clock = func() time.Time { return time.Unix(0, 0) }
+	Register(g, s)
+	go g.Serve(listener)
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return decidepb.NewDecisionServiceClient(conn)
+}
+
+func TestConvertInput(t *testing.T) {
+	input := decide.Generate(11)
+	m, err := InputToProto(input)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	back, err := InputFromProto(m)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	a, _ := decide.Hash(input)
+	b, _ := decide.Hash(back)
+	if a != b {
+		t.Error("Expected the same input after a round trip")
+		return
+	}
+
+	m.Lcm = m.Lcm[1:]
+	if _, err := InputFromProto(m); err == nil {
+		t.Error("Expected an error for a missing LCM row")
+		return
+	}
+}
+
+func TestConvertResult(t *testing.T) {
+	d := decide.Decide{}
+	if err := d.Decide(decide.Generate(12)); err != nil {
+		t.Error(err)
+		return
+	}
+	d.SetProvenance("x.json", time.Unix(10, 0))
+	back, err := ResultFromProto(ResultToProto(d))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if diffs := decide.Compare(d, back); diffs != nil {
+		t.Error("Unexpected differences", diffs)
+		return
+	}
+	if *back.Provenance.RandomSeed != 12 || !back.Provenance.Timestamp.Equal(time.Unix(10, 0)) {
+		t.Error("Unexpected provenance", back.Provenance)
+		return
+	}
+
+	// The LICs skipped by a lazy evaluation are not false CMV entries.
+	input := decide.Generate(12)
+	input.PUV = [decide.NB_LIC]bool{3: true}
+	e := decide.Engine{Lazy: true}
+	d, err = e.Decide(context.Background(), input)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	back, err = ResultFromProto(ResultToProto(d))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(d.NotEvaluated) == 0 || !reflect.DeepEqual(back.NotEvaluated, d.NotEvaluated) {
+		t.Error("Expected the LICs not evaluated after a round trip, got", back.NotEvaluated, d.NotEvaluated)
+		return
+	}
+}
+
+// TestConvertProvenance checks that a result converted to protobuf and
+// back replays with the engine that produced it.
+func TestConvertProvenance(t *testing.T) {
+	e := decide.Engine{
+		Evaluation: decide.EvaluationStrict,
+		MaxPoints:  200,
+		Bounds:     &decide.Bounds{Min: [2]float64{-1e7, -1e7}, Max: [2]float64{1e7, 1e7}},
+		Lazy:       true,
+	}
+	d, err := e.Decide(context.Background(), decide.Generate(14))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	d.SetProvenance("x.json", time.Unix(10, 0))
+	back, err := ResultFromProto(ResultToProto(d))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !reflect.DeepEqual(back.Provenance, d.Provenance) {
+		t.Error("Expected the same provenance after a round trip, got", back.Provenance, d.Provenance)
+		return
+	}
+	if !reflect.DeepEqual(back.Provenance.Engine(), &e) {
+		t.Error("Expected the engine of the provenance, got", back.Provenance.Engine())
+		return
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	client := newClient(t)
+	input := decide.Generate(13)
+	m, _ := InputToProto(input)
+	response, err := client.Evaluate(context.Background(), &decidepb.EvaluateRequest{Id: "a", Input: m})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	result, err := ResultFromProto(response.GetResult())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expected := decide.Decide{}
+	expected.Decide(input)
+	if response.Id != "a" || decide.Compare(expected, result) != nil {
+		t.Error("Unexpected response", response)
+		return
+	}
+
+	m.Parameters.Quads = 7
+	_, err = client.Evaluate(context.Background(), &decidepb.EvaluateRequest{Input: m})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Error("Expected InvalidArgument, got", err)
+		return
+	}
+
+	// Unlike JSON, protobuf carries non-finite numbers.
+	m, _ = InputToProto(input)
+	m.Points[3].X = math.NaN()
+	_, err = client.Evaluate(context.Background(), &decidepb.EvaluateRequest{Input: m})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Error("Expected InvalidArgument for a NaN coordinate, got", err)
+		return
+	}
+}
+
+func TestEvaluateStream(t *testing.T) {
+	client := newClient(t)
+	stream, err := client.EvaluateStream(context.Background())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	valid, _ := InputToProto(decide.Generate(14))
+	invalid, _ := InputToProto(decide.Generate(15))
+	invalid.Parameters.Quads = 7
+	requests := []*decidepb.EvaluateRequest{{Id: "1", Input: valid}, {Id: "2", Input: invalid}, {Id: "3", Input: valid}}
+	for _, req := range requests {
+		if err := stream.Send(req); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	stream.CloseSend()
+
+	var responses []*decidepb.EvaluateResponse
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		responses = append(responses, response)
+	}
+	if len(responses) != 3 {
+		t.Error("Expected 3 responses, got", len(responses))
+		return
+	}
+	if responses[0].GetResult() == nil || responses[1].GetError().GetField() != "QUADS" || responses[2].Id != "3" {
+		t.Error("Unexpected responses", responses)
+		return
+	}
+}
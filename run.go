@@ -0,0 +1,209 @@
+package main
+
+import (
+	"github.com/tdurieux/go-decide/audit"
+	"github.com/tdurieux/go-decide/decide"
+	"github.com/tdurieux/go-decide/metrics"
+	"github.com/tdurieux/go-decide/sign"
+	"crypto/ed25519"
+	"context"
+	"fmt"
+	"encoding/json"
+	"os"
+	"io/ioutil"
+	"path"
+	"io"
+	"time"
+)
+
+// streamResult is the line written to stdout for each input document
+// read in streaming mode. Only one of the two parts is set.
+type streamResult struct {
+	*decide.Decide
+	Error string `json:"ERROR,omitempty"`
+}
+
+// stream reads concatenated INPUT documents from r and writes one JSON
+// result per line to w, in the same order. It returns the exit code of
+// the whole stream under policy.
+func stream(r io.Reader, w io.Writer, engine *decide.Engine, auditLog *audit.Log, policy exitPolicy, clock func() time.Time) (int, error) {
+	decoder := json.NewDecoder(r)
+	encoder := json.NewEncoder(w)
+	code := exitOK
+	for {
+		var input decide.INPUT
+		err := decoder.Decode(&input)
+		if err == io.EOF {
+			return code, nil
+		}
+		if err != nil {
+			return code, inputError{err}
+		}
+		decision, err := engine.Decide(context.Background(), input)
+		result := streamResult{}
+		if err != nil {
+			result.Error = err.Error()
+			code = max(code, exitCode(err))
+		} else if err := record(auditLog, input, &decision, "-", clock); err != nil {
+			result.Error = err.Error()
+			code = max(code, exitInternal)
+		} else {
+			result.Decide = &decision
+			code = max(code, policy.code(decision.Launch))
+		}
+		if err := encoder.Encode(result); err != nil {
+			return code, err
+		}
+	}
+}
+
+// record sets the provenance of decision and appends it to the audit
+// log, if any.
+func record(auditLog *audit.Log, input decide.INPUT, decision *decide.Decide, inputPath string, clock func() time.Time) error {
+	if err := decision.SetProvenance(inputPath, clock()); err != nil {
+		return err
+	}
+	if auditLog == nil {
+		return nil
+	}
+	_, err := auditLog.Append(input, *decision, clock())
+	return err
+}
+
+func execute(engine *decide.Engine, auditLog *audit.Log, signKey ed25519.PrivateKey, filePath string, outputDir string, clock func() time.Time) (decide.Decide, error) {
+	input, err := getInput(filePath)
+	if err != nil {
+		return decide.Decide{}, err
+	}
+
+	decision, err := engine.Decide(context.Background(), input)
+	if err != nil {
+		return decision, err
+	}
+	if err := record(auditLog, input, &decision, filePath, clock); err != nil {
+		return decision, err
+	}
+	if outputDir != "" {
+		content, err := serializeDecision(decision)
+		if err != nil {
+			return decision, err
+		}
+		if signKey != nil {
+			if content, err = signResult(content, signKey); err != nil {
+				return decision, err
+			}
+		}
+		if err := os.MkdirAll(outputDir, 0700); err != nil {
+			return decision, err
+		}
+		outputFile := path.Join(outputDir, path.Base(filePath))
+		if err := ioutil.WriteFile(outputFile, content, 0644); err != nil {
+			return decision, err
+		}
+	}
+	return decision, nil
+}
+
+func runCmd(args []string) int {
+	flags := newFlagSet("run", "[input]",
+		"Evaluates an input file, every json file of a directory, or the\n" +
+		"concatenated inputs read from stdin when the input is -.")
+	filePath := flags.String("input", "", "the path to the input, - for stdin")
+	outputPath := flags.String("output", "", "the path to the output")
+	timestamp := flags.String("timestamp", "", "the RFC 3339 timestamp recorded in the provenance of results, for reproducible outputs")
+	logLevel := flags.String("log-level", "warn", "the minimum level of the events of the engine logged to stderr: debug, info, warn or error")
+	trace := flags.Bool("trace", false, "attach to every result the trace of its derivation")
+	auditPath := flags.String("audit-log", "", "the path of an audit log where every decision is appended")
+	auditKey := flags.String("audit-key", "", "the path of the private key that signs the audit log entries")
+	signKeyPath := flags.String("sign-key", "", "the path of the private key that signs the output files")
+	maxPoints := flags.Int("max-points", decide.DefaultMaxPoints, "the maximum NUMPOINTS of an input")
+	bounds := boundsFlag(flags)
+	evaluation := evaluationFlag(flags)
+	lazy := flags.Bool("lazy", false, "skip the LICs that cannot change the launch decision")
+	concurrency := flags.Int("concurrency", 1, "the number of LICs evaluated concurrently")
+	metricsPath := flags.String("metrics", "", "the path of a file where the metrics of the run are written in the Prometheus text format")
+	policy := exitPolicyFlag(flags)
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+	clock, err := newClock(*timestamp)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInput
+	}
+
+	if *filePath == "" {
+		*filePath = flags.Arg(0)
+	}
+	// The results of stdin are written to stdout, neither to files of
+	// -output nor signed.
+	if *filePath == "-" && (*outputPath != "" || *signKeyPath != "") {
+		fmt.Fprintln(os.Stderr, "-output and -sign-key cannot be used with stdin")
+		return exitInput
+	}
+	logger, err := newLogger(*logLevel)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInput
+	}
+	engine := &decide.Engine{Logger: logger, Trace: *trace, MaxPoints: *maxPoints, Bounds: *bounds, Evaluation: *evaluation, Concurrency: *concurrency, Lazy: *lazy}
+	auditLog, err := openAuditLog(*auditPath, *auditKey)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "unable to open the audit log", err.Error())
+		return exitCode(err)
+	}
+	var signKey ed25519.PrivateKey
+	if *signKeyPath != "" {
+		if signKey, err = sign.LoadPrivateKey(*signKeyPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitInput
+		}
+	}
+	if *metricsPath != "" {
+		m := metrics.New(*maxPoints)
+		engine.Observer = m
+		defer func() {
+			if err := m.WriteFile(*metricsPath); err != nil {
+				fmt.Fprintln(os.Stderr, "unable to write the metrics", err.Error())
+			}
+		}()
+	}
+	if *filePath == "-" {
+		code, err := stream(os.Stdin, os.Stdout, engine, auditLog, *policy, clock)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "unable to read the input", err.Error())
+			return max(code, exitCode(err))
+		}
+		return code
+	}
+	if *filePath == "" {
+		flags.Usage()
+		return exitInput
+	}
+	fi, err := os.Stat(*filePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInput
+	}
+	files, err := inputFiles(*filePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInput
+	}
+	code := exitOK
+	for _, file := range files {
+		if fi.IsDir() {
+			fmt.Print(path.Base(file) + " ")
+		}
+		decide, err := execute(engine, auditLog, signKey, file, *outputPath, clock)
+		if err != nil {
+			fmt.Println("ERROR")
+			fmt.Fprintln(os.Stderr, file + ":", err)
+			code = max(code, exitCode(err))
+			continue
+		}
+		fmt.Println(decide.Launch)
+		code = max(code, policy.code(decide.Launch))
+	}
+	return code
+}
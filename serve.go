@@ -0,0 +1,87 @@
+package main
+
+import (
+	"github.com/tdurieux/go-decide/decide"
+	"github.com/tdurieux/go-decide/metrics"
+	"github.com/tdurieux/go-decide/rpc"
+	"github.com/tdurieux/go-decide/server"
+	"google.golang.org/grpc"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+func serveCmd(args []string) int {
+	flags := newFlagSet("serve", "",
+		"Serves the decision engine over HTTP: POST /v1/decide, /v1/validate,\n" +
+		"/v1/explain and /v1/batch, and optionally over gRPC. The metrics of\n" +
+		"the engine are served on GET /metrics.")
+	addr := flags.String("addr", ":8080", "the address to listen on")
+	maxBody := flags.Int64("max-body", 1 << 20, "the maximum size of a request body in bytes")
+	grpcAddr := flags.String("grpc-addr", "", "the address to serve gRPC on, disabled if empty")
+	logLevel := flags.String("log-level", "info", "the minimum level of the events logged to stderr: debug, info, warn or error")
+	auditPath := flags.String("audit-log", "", "the path of an audit log where every decision is appended")
+	auditKey := flags.String("audit-key", "", "the path of the private key that signs the audit log entries")
+	maxPoints := flags.Int("max-points", decide.DefaultMaxPoints, "the maximum NUMPOINTS of an input")
+	bounds := boundsFlag(flags)
+	evaluation := evaluationFlag(flags)
+	lazy := flags.Bool("lazy", false, "skip the LICs that cannot change the launch decision")
+	concurrency := flags.Int("concurrency", 1, "the number of LICs evaluated concurrently")
+	timeout := flags.Duration("timeout", 5 * time.Second, "the maximum duration of the evaluation of a request")
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return exitInput
+	}
+
+	logger, err := newLogger(*logLevel)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInput
+	}
+	auditLog, err := openAuditLog(*auditPath, *auditKey)
+	if err != nil {
+		logger.Error("unable to open the audit log", "path", *auditPath, "error", err)
+		return exitCode(err)
+	}
+	m := metrics.New(*maxPoints)
+	engine := &decide.Engine{Observer: m, Logger: logger, MaxPoints: *maxPoints, Bounds: *bounds, Evaluation: *evaluation, Concurrency: *concurrency, Lazy: *lazy}
+	s := server.New()
+	s.MaxBodySize = *maxBody
+	s.Timeout = *timeout
+	s.Engine = engine
+	s.Audit = auditLog
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	mux.Handle("/", s.Handler())
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if *grpcAddr != "" {
+		listener, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			logger.Error("unable to listen", "addr", *grpcAddr, "error", err)
+			return exitInternal
+		}
+		g := grpc.NewServer()
+		rpcServer := rpc.NewServer()
+		rpcServer.Engine = engine
+		rpcServer.Audit = auditLog
+		rpc.Register(g, rpcServer)
+		logger.Info("gRPC listening", "addr", *grpcAddr)
+		go g.Serve(listener)
+		defer g.Stop()
+	}
+	logger.Info("HTTP listening", "addr", *addr)
+	if err := httpServer.ListenAndServe(); err != nil {
+		logger.Error("unable to serve", "addr", *addr, "error", err)
+		return exitInternal
+	}
+	return exitOK
+}
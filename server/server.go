@@ -0,0 +1,217 @@
+// Package server exposes the decision engine over HTTP.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/tdurieux/go-decide/audit"
+	"github.com/tdurieux/go-decide/decide"
+)
+
+// Server serves the decision endpoints:
+//
+//	POST /v1/decide    INPUT in, full result out
+//	POST /v1/validate  INPUT in, validity out
+//	POST /v1/explain   INPUT in, explanation out
+//	POST /v1/batch     array of INPUT in, array of results out
+type Server struct {
+	// MaxBodySize is the maximum size of a request body in bytes.
+	MaxBodySize int64
+	// Timeout bounds the evaluation of a request.
+	Timeout time.Duration
+	// Clock returns the timestamp recorded in the provenance of results.
+	Clock func() time.Time
+	// Engine evaluates the inputs.
+	Engine *decide.Engine
+	// Audit, when set, records every decision of /v1/decide and /v1/batch.
+	Audit *audit.Log
+}
+
+// New returns a server with a 1 MiB body limit and a 5 seconds timeout.
+func New() *Server {
+	return &Server{
+		MaxBodySize: 1 << 20,
+		Timeout:     5 * time.Second,
+		Clock:       time.Now,
+		Engine:      &decide.Engine{},
+	}
+}
+
+// Result is a result of the batch endpoint. Only one of the two parts is set.
+type Result struct {
+	*decide.Decide
+	Error string `json:"ERROR,omitempty"`
+}
+
+// Error is the body of every non 2xx response.
+type Error struct {
+	Error string `json:"ERROR"`
+	// Field is the offending input field of a validation error.
+	Field string `json:"FIELD,omitempty"`
+}
+
+// Handler returns the handler of the endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/decide", s.post(s.handleDecide))
+	mux.HandleFunc("/v1/validate", s.post(s.handleValidate))
+	mux.HandleFunc("/v1/explain", s.post(s.handleExplain))
+	mux.HandleFunc("/v1/batch", s.post(s.handleBatch))
+	return mux
+}
+
+// post restricts h to POST requests and applies the size limit and the
+// timeout of the server.
+func (s *Server) post(h func(ctx context.Context, w http.ResponseWriter, r *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, Error{Error: "method not allowed"})
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, s.MaxBodySize)
+		ctx, cancel := context.WithTimeout(r.Context(), s.Timeout)
+		defer cancel()
+		h(ctx, w, r)
+	}
+}
+
+func (s *Server) handleDecide(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var input decide.INPUT
+	if err := decode(r, &input); err != nil {
+		writeError(w, err)
+		return
+	}
+	decision, err := s.decide(ctx, input)
+	if err == nil {
+		err = s.audit(input, decision)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, decision)
+}
+
+func (s *Server) handleValidate(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var input decide.INPUT
+	if err := decode(r, &input); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.Engine.Validate(input); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Valid bool `json:"VALID"`
+	}{true})
+}
+
+func (s *Server) handleExplain(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var input decide.INPUT
+	if err := decode(r, &input); err != nil {
+		writeError(w, err)
+		return
+	}
+	decision, err := s.decide(ctx, input)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, decision.Explain())
+}
+
+func (s *Server) handleBatch(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var inputs []decide.INPUT
+	if err := decode(r, &inputs); err != nil {
+		writeError(w, err)
+		return
+	}
+	results := make([]Result, len(inputs))
+	for i, input := range inputs {
+		decision, err := s.decide(ctx, input)
+		if err != nil {
+			// a timeout fails the whole batch, an invalid input only its result
+			if ctx.Err() != nil {
+				writeError(w, ctx.Err())
+				return
+			}
+			results[i].Error = err.Error()
+			continue
+		}
+		if err := s.audit(input, decision); err != nil {
+			writeError(w, err)
+			return
+		}
+		results[i].Decide = &decision
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+func (s *Server) decide(ctx context.Context, input decide.INPUT) (decide.Decide, error) {
+	decision, err := s.Engine.Decide(ctx, input)
+	if err != nil {
+		return decision, err
+	}
+	if err := decision.SetProvenance("-", s.Clock()); err != nil {
+		return decision, err
+	}
+	return decision, nil
+}
+
+func (s *Server) audit(input decide.INPUT, decision decide.Decide) error {
+	if s.Audit == nil {
+		return nil
+	}
+	_, err := s.Audit.Append(input, decision, s.Clock())
+	return err
+}
+
+// badRequest marks a body that is not the expected JSON document.
+type badRequest struct {
+	error
+}
+
+func decode(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return err
+		}
+		return badRequest{err}
+	}
+	return nil
+}
+
+// writeError maps err to its HTTP status code.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	body := Error{Error: err.Error()}
+	var validation *decide.ValidationError
+	var tooLarge *http.MaxBytesError
+	var bad badRequest
+	switch {
+	case errors.As(err, &validation):
+		status = http.StatusUnprocessableEntity
+		body.Field = validation.Field
+	case errors.As(err, &tooLarge):
+		status = http.StatusRequestEntityTooLarge
+	case errors.As(err, &bad):
+		status = http.StatusBadRequest
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tdurieux/go-decide/audit"
+	"github.com/tdurieux/go-decide/decide"
+)
+
+func post(t *testing.T, s *Server, path string, body interface{}) *httptest.ResponseRecorder {
+	content, ok := body.([]byte)
+	if !ok {
+		var err error
+		if content, err = json.Marshal(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(content))
+	w := httptest.NewRecorder()
+	s.Handler().ServeHTTP(w, r)
+	return w
+}
+
+func newTestServer() *Server {
+	s := New()
+	s.Clock = func() time.Time { return time.Unix(0, 0) }
+	return s
+}
+
+func TestDecide(t *testing.T) {
+	input := decide.Generate(1)
+	w := post(t, newTestServer(), "/v1/decide", input)
+	if w.Code != http.StatusOK {
+		t.Error("Expected 200, got", w.Code, w.Body)
+		return
+	}
+	var result decide.Decide
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Error(err)
+		return
+	}
+	expected := decide.Decide{}
+	expected.Decide(input)
+	if diffs := decide.Compare(expected, result); diffs != nil {
+		t.Error("Unexpected result", diffs)
+		return
+	}
+	if result.Provenance == nil || !result.Provenance.Timestamp.Equal(time.Unix(0, 0)) {
+		t.Error("Expected a provenance", result.Provenance)
+		return
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := newTestServer()
+	input := decide.Generate(2)
+	if w := post(t, s, "/v1/validate", input); w.Code != http.StatusOK {
+		t.Error("Expected 200, got", w.Code, w.Body)
+		return
+	}
+
+	input.Parameters.QUADS = 5
+	w := post(t, s, "/v1/validate", input)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Error("Expected 422, got", w.Code)
+		return
+	}
+	var body Error
+	json.Unmarshal(w.Body.Bytes(), &body)
+	if body.Field != "QUADS" {
+		t.Error("Expected an error on QUADS, got", body)
+		return
+	}
+
+	if w := post(t, s, "/v1/decide", input); w.Code != http.StatusUnprocessableEntity {
+		t.Error("Expected 422, got", w.Code)
+		return
+	}
+}
+
+func TestExplain(t *testing.T) {
+	input := decide.Generate(3)
+	w := post(t, newTestServer(), "/v1/explain", input)
+	if w.Code != http.StatusOK {
+		t.Error("Expected 200, got", w.Code, w.Body)
+		return
+	}
+	var explanation decide.Explanation
+	if err := json.Unmarshal(w.Body.Bytes(), &explanation); err != nil {
+		t.Error(err)
+		return
+	}
+	if explanation.Launch != "YES" && len(explanation.Reasons) == 0 {
+		t.Error("Expected reasons for a NO", explanation)
+		return
+	}
+}
+
+func TestBatch(t *testing.T) {
+	invalid := decide.Generate(5)
+	invalid.NumPoints = 1
+	inputs := []decide.INPUT{decide.Generate(4), invalid}
+	w := post(t, newTestServer(), "/v1/batch", inputs)
+	if w.Code != http.StatusOK {
+		t.Error("Expected 200, got", w.Code, w.Body)
+		return
+	}
+	var results []Result
+	if err := json.Unmarshal(w.Body.Bytes(), &results); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(results) != 2 || results[0].Decide == nil || results[0].Error != "" ||
+		results[1].Decide != nil || results[1].Error == "" {
+		t.Error("Unexpected results", w.Body)
+		return
+	}
+}
+
+func TestErrors(t *testing.T) {
+	s := newTestServer()
+	if w := post(t, s, "/v1/decide", []byte("{")); w.Code != http.StatusBadRequest {
+		t.Error("Expected 400, got", w.Code)
+		return
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/decide", nil)
+	w := httptest.NewRecorder()
+	s.Handler().ServeHTTP(w, r)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Error("Expected 405, got", w.Code)
+		return
+	}
+
+	s.MaxBodySize = 10
+	if w := post(t, s, "/v1/decide", decide.Generate(1)); w.Code != http.StatusRequestEntityTooLarge {
+		t.Error("Expected 413, got", w.Code)
+		return
+	}
+
+	s = newTestServer()
+	s.Timeout = 0
+	w = post(t, s, "/v1/batch", []decide.INPUT{decide.Generate(1)})
+	if w.Code != http.StatusServiceUnavailable {
+		t.Error("Expected 503, got", w.Code)
+		return
+	}
+	if !strings.Contains(w.Body.String(), "deadline") {
+		t.Error("Expected a deadline error", w.Body)
+		return
+	}
+}
+
+func TestAudit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l, err := audit.Open(path, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	s := newTestServer()
+	s.Audit = l
+	post(t, s, "/v1/decide", decide.Generate(1))
+	post(t, s, "/v1/batch", []decide.INPUT{decide.Generate(2), decide.Generate(3)})
+	post(t, s, "/v1/explain", decide.Generate(4))
+	n, err := audit.Verify(path, nil)
+	if err != nil || n != 3 {
+		t.Error("Expected 3 audited decisions, got", n, err)
+		return
+	}
+}
@@ -0,0 +1,41 @@
+package sign
+
+import (
+	"crypto/ed25519"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateKey(t *testing.T) {
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "decide.key")
+	publicPath := filepath.Join(dir, "decide.pub")
+	if err := GenerateKey(privatePath, publicPath); err != nil {
+		t.Error(err)
+		return
+	}
+	private, err := LoadPrivateKey(privatePath)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	public, err := LoadPublicKey(publicPath)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	signature := ed25519.Sign(private, []byte("decide"))
+	if !ed25519.Verify(public, []byte("decide"), signature) {
+		t.Error("Expected the keys to match")
+		return
+	}
+
+	if err := GenerateKey(privatePath, publicPath); err == nil {
+		t.Error("Expected existing keys not to be overwritten")
+		return
+	}
+	if _, err := LoadPublicKey(privatePath); err == nil {
+		t.Error("Expected a private key not to load as a public key")
+		return
+	}
+}
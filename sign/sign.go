@@ -0,0 +1,158 @@
+package sign
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Algorithm is the only signature algorithm supported.
+const Algorithm = "ed25519"
+
+// field is the key of the signature embedded in a result.
+const field = "SIGNATURE"
+
+// Signature signs a result file. It is either embedded in the result under
+// the SIGNATURE key or stored in a detached file.
+type Signature struct {
+	Algorithm string `json:"ALGORITHM"`
+	// KeyID identifies the public key that verifies the signature.
+	KeyID string `json:"KEY_ID"`
+	// InputSHA256 is the hash of the input of the result, from its
+	// provenance.
+	InputSHA256 string `json:"INPUT_SHA256"`
+	// Signature is the hex encoded signature of Message.
+	Signature string `json:"SIGNATURE"`
+}
+
+// ErrUnsigned is returned when a result carries no embedded signature.
+var ErrUnsigned = errors.New("the result is not signed")
+
+// KeyID returns the identifier of a public key: the first 16 hex digits
+// of the SHA-256 of the key.
+func KeyID(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Canonical returns the canonical JSON encoding of a result: its objects
+// with keys sorted, no whitespace, numbers as written and without the
+// embedded signature. Reformatting a result does not change it.
+func Canonical(result []byte) ([]byte, error) {
+	var fields map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(result))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+	delete(fields, field)
+	return json.Marshal(fields)
+}
+
+// inputHash returns the INPUT_SHA256 of the provenance of the result.
+func inputHash(result []byte) (string, error) {
+	var r struct {
+		Provenance *struct {
+			InputSHA256 string `json:"INPUT_SHA256"`
+		} `json:"PROVENANCE"`
+	}
+	if err := json.Unmarshal(result, &r); err != nil {
+		return "", err
+	}
+	if r.Provenance == nil || r.Provenance.InputSHA256 == "" {
+		return "", errors.New("the result has no provenance")
+	}
+	return r.Provenance.InputSHA256, nil
+}
+
+// Message returns the signed content: the input hash and the canonical
+// result, separated by a new line.
+func Message(result []byte, inputSHA256 string) ([]byte, error) {
+	canonical, err := Canonical(result)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(inputSHA256 + "\n"), canonical...), nil
+}
+
+// Sign signs a result that carries its provenance.
+func Sign(result []byte, key ed25519.PrivateKey) (Signature, error) {
+	hash, err := inputHash(result)
+	if err != nil {
+		return Signature{}, err
+	}
+	message, err := Message(result, hash)
+	if err != nil {
+		return Signature{}, err
+	}
+	return Signature{
+		Algorithm:   Algorithm,
+		KeyID:       KeyID(key.Public().(ed25519.PublicKey)),
+		InputSHA256: hash,
+		Signature:   hex.EncodeToString(ed25519.Sign(key, message)),
+	}, nil
+}
+
+// Verify checks that sig is a signature of result by one of keys.
+func Verify(result []byte, sig Signature, keys ...ed25519.PublicKey) error {
+	if sig.Algorithm != Algorithm {
+		return fmt.Errorf("unsupported signature algorithm %q", sig.Algorithm)
+	}
+	hash, err := inputHash(result)
+	if err != nil {
+		return err
+	}
+	if hash != sig.InputSHA256 {
+		return errors.New("the input hash of the result does not match the signature")
+	}
+	message, err := Message(result, sig.InputSHA256)
+	if err != nil {
+		return err
+	}
+	signature, err := hex.DecodeString(sig.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %s", err)
+	}
+	for _, key := range keys {
+		if KeyID(key) == sig.KeyID {
+			if !ed25519.Verify(key, message, signature) {
+				return errors.New("invalid signature, the result was modified")
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("the result is signed by the unknown key %s", sig.KeyID)
+}
+
+// Embed returns the result with sig under its SIGNATURE key, replacing
+// any previous signature.
+func Embed(result []byte, sig Signature) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(result, &fields); err != nil {
+		return nil, err
+	}
+	content, err := json.Marshal(sig)
+	if err != nil {
+		return nil, err
+	}
+	fields[field] = content
+	return json.MarshalIndent(fields, "", "  ")
+}
+
+// Extract returns the signature embedded in a result, or ErrUnsigned.
+func Extract(result []byte) (Signature, error) {
+	var r struct {
+		Signature *Signature `json:"SIGNATURE"`
+	}
+	if err := json.Unmarshal(result, &r); err != nil {
+		return Signature{}, err
+	}
+	if r.Signature == nil {
+		return Signature{}, ErrUnsigned
+	}
+	return *r.Signature, nil
+}
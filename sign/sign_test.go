@@ -0,0 +1,129 @@
+package sign
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/tdurieux/go-decide/decide"
+)
+
+func signedResult(t *testing.T) ([]byte, ed25519.PublicKey, ed25519.PrivateKey) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := decide.Decide{}
+	if err := d.Decide(decide.Generate(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetProvenance("input.json", time.Unix(0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	result, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result, public, private
+}
+
+func TestSign(t *testing.T) {
+	result, public, private := signedResult(t)
+	sig, err := Sign(result, private)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := Verify(result, sig, public); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, result); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := Verify(compact.Bytes(), sig, public); err != nil {
+		t.Error("Expected reformatting not to break the signature", err)
+		return
+	}
+
+	modified := bytes.Replace(result, []byte(`"LAUNCH": "`), []byte(`"LAUNCH": "X`), 1)
+	if err := Verify(modified, sig, public); err == nil {
+		t.Error("Expected a modified result to fail verification")
+		return
+	}
+
+	other, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := Verify(result, sig, other); err == nil {
+		t.Error("Expected an unknown key to fail verification")
+		return
+	}
+	if err := Verify(result, sig, other, public); err != nil {
+		t.Error("Expected one of the keys to verify the signature", err)
+		return
+	}
+}
+
+func TestSignInputHash(t *testing.T) {
+	result, public, private := signedResult(t)
+	sig, err := Sign(result, private)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	sig.InputSHA256 = "0000"
+	if err := Verify(result, sig, public); err == nil {
+		t.Error("Expected a different input hash to fail verification")
+		return
+	}
+
+	if _, err := Sign([]byte(`{"LAUNCH":"YES"}`), private); err == nil {
+		t.Error("Expected a result without provenance not to be signed")
+		return
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	result, public, private := signedResult(t)
+	if _, err := Extract(result); err != ErrUnsigned {
+		t.Error("Expected ErrUnsigned, got", err)
+		return
+	}
+	sig, err := Sign(result, private)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	embedded, err := Embed(result, sig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	extracted, err := Extract(embedded)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if extracted != sig {
+		t.Error("Expected the embedded signature", sig, "got", extracted)
+		return
+	}
+	if err := Verify(embedded, extracted, public); err != nil {
+		t.Error(err)
+		return
+	}
+	var d decide.Decide
+	if err := json.Unmarshal(embedded, &d); err != nil {
+		t.Error("Expected a signed result to decode as a result", err)
+		return
+	}
+}
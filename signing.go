@@ -0,0 +1,170 @@
+package main
+
+import (
+	"github.com/tdurieux/go-decide/decide"
+	"github.com/tdurieux/go-decide/sign"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// detachedPath returns the path of the detached signature of a result.
+func detachedPath(resultPath string) string {
+	return resultPath + ".sig"
+}
+
+// keysValue is a repeatable flag of public key files.
+type keysValue struct {
+	keys  *[]ed25519.PublicKey
+	paths []string
+}
+
+func (v *keysValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return strings.Join(v.paths, ",")
+}
+
+func (v *keysValue) Set(value string) error {
+	key, err := sign.LoadPublicKey(value)
+	if err != nil {
+		return err
+	}
+	*v.keys = append(*v.keys, key)
+	v.paths = append(v.paths, value)
+	return nil
+}
+
+// signResult signs a serialized result with key and embeds the signature.
+func signResult(content []byte, key ed25519.PrivateKey) ([]byte, error) {
+	sig, err := sign.Sign(content, key)
+	if err != nil {
+		return nil, err
+	}
+	return sign.Embed(content, sig)
+}
+
+func signCmd(args []string) int {
+	flags := newFlagSet("sign", "result...",
+		"Signs result files written by run -output. The signature is embedded\n" +
+		"in the result, or written next to it in result.sig with -detached.")
+	keyPath := flags.String("key", "decide.key", "the path of the private key")
+	detached := flags.Bool("detached", false, "write the signature to a separate .sig file")
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitInput
+	}
+	key, err := sign.LoadPrivateKey(*keyPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInput
+	}
+	code := exitOK
+	for _, resultPath := range flags.Args() {
+		if err := signFile(resultPath, key, *detached); err != nil {
+			fmt.Fprintln(os.Stderr, resultPath + ":", err)
+			code = max(code, exitInput)
+		}
+	}
+	return code
+}
+
+func signFile(resultPath string, key ed25519.PrivateKey, detached bool) error {
+	content, err := ioutil.ReadFile(resultPath)
+	if err != nil {
+		return err
+	}
+	if !detached {
+		signed, err := signResult(content, key)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(resultPath, signed, 0644)
+	}
+	sig, err := sign.Sign(content, key)
+	if err != nil {
+		return err
+	}
+	// Embed into an empty object to serialize the signature alone.
+	detachedContent, err := sign.Embed([]byte("{}"), sig)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(detachedPath(resultPath), detachedContent, 0644)
+}
+
+func verifyCmd(args []string) int {
+	flags := newFlagSet("verify", "result...",
+		"Verifies that result files are signed by an approved key, with the\n" +
+		"signature embedded in the result or in result.sig.")
+	var keys []ed25519.PublicKey
+	flags.Var(&keysValue{keys: &keys}, "pub", "the path of an approved public key, repeatable")
+	inputPath := flags.String("input", "", "the path of the input, to check that the result is the decision of this input")
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+	if flags.NArg() == 0 || len(keys) == 0 {
+		flags.Usage()
+		return exitInput
+	}
+	inputHash := ""
+	if *inputPath != "" {
+		input, err := getInput(*inputPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitInput
+		}
+		if inputHash, err = decide.Hash(input); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitInput
+		}
+	}
+	code := exitOK
+	for _, resultPath := range flags.Args() {
+		err := verifyFile(resultPath, keys, inputHash)
+		var pathErr *os.PathError
+		switch {
+		case err == nil:
+			fmt.Println(resultPath + ": OK")
+		case errors.As(err, &pathErr):
+			fmt.Fprintln(os.Stderr, err)
+			code = max(code, exitInput)
+		default:
+			fmt.Println(resultPath + ":", err)
+			code = max(code, exitNo)
+		}
+	}
+	return code
+}
+
+func verifyFile(resultPath string, keys []ed25519.PublicKey, inputHash string) error {
+	content, err := ioutil.ReadFile(resultPath)
+	if err != nil {
+		return err
+	}
+	sig, err := sign.Extract(content)
+	if err == sign.ErrUnsigned {
+		detachedContent, derr := ioutil.ReadFile(detachedPath(resultPath))
+		if os.IsNotExist(derr) {
+			return err
+		}
+		if derr != nil {
+			return derr
+		}
+		sig, err = sign.Extract(detachedContent)
+	}
+	if err != nil {
+		return err
+	}
+	if inputHash != "" && sig.InputSHA256 != inputHash {
+		return errors.New("the result is not the decision of the input")
+	}
+	return sign.Verify(content, sig, keys...)
+}
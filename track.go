@@ -0,0 +1,84 @@
+package main
+
+import (
+	"github.com/tdurieux/go-decide/decide"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// trackEvent is the line written to stdout each time the decision of a
+// track changes.
+type trackEvent struct {
+	// Point is the index of the point that changed the decision, from 0.
+	Point  int        `json:"POINT"`
+	Launch string     `json:"LAUNCH"`
+	CMV    decide.Cmv `json:"CMV"`
+	FUV    decide.Fuv `json:"FUV"`
+}
+
+func trackCmd(args []string) int {
+	flags := newFlagSet("track", "config",
+		"Evaluates a radar track read from stdin as a sequence of [x, y]\n" +
+		"points, over a sliding window of the last points. The LCM, PUV and\n" +
+		"PARAMETERS come from the config input file. A line is printed each\n" +
+		"time the CMV, FUV or LAUNCH of the window changes.")
+	size := flags.Int("window", 0, "the number of points of the window, NUMPOINTS of the config when 0")
+	maxPoints := flags.Int("max-points", decide.DefaultMaxPoints, "the maximum number of points of the window")
+	bounds := boundsFlag(flags)
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitInput
+	}
+	config, err := getInput(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInput
+	}
+	if *size == 0 {
+		*size = config.NumPoints
+	}
+	engine := &decide.Engine{MaxPoints: *maxPoints, Bounds: *bounds}
+	stream, err := engine.NewStream(config, *size)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInput
+	}
+	if err := track(os.Stdin, os.Stdout, stream, *bounds); err != nil {
+		fmt.Fprintln(os.Stderr, "unable to read the track", err.Error())
+		return exitCode(err)
+	}
+	return exitOK
+}
+
+// track pushes the points read from r to stream and writes an event to
+// w for each change of its decision. It stops at the first point out of
+// bounds.
+func track(r io.Reader, w io.Writer, stream *decide.Stream, bounds *decide.Bounds) error {
+	decoder := json.NewDecoder(r)
+	encoder := json.NewEncoder(w)
+	for i := 0; ; i++ {
+		var p [2]float64
+		err := decoder.Decode(&p)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return inputError{err}
+		}
+		if err := bounds.Check(i, p); err != nil {
+			return err
+		}
+		d, changed := stream.Push(p)
+		if !changed {
+			continue
+		}
+		if err := encoder.Encode(trackEvent{i, d.Launch, d.CMV, d.FUV}); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/tdurieux/go-decide/decide"
+	"github.com/tdurieux/go-decide/tui"
+	"fmt"
+	"os"
+	"path"
+)
+
+func tuiCmd(args []string) int {
+	flags := newFlagSet("tui", "input",
+		"Explores the decision of an input in the terminal: shows its CMV,\n" +
+		"its PUM as a coloured grid and its FUV, and decides it again after\n" +
+		"each command that toggles a PUV entry, edits an LCM cell or sets a\n" +
+		"parameter, highlighting the cells that changed.\n\n" +
+		tui.Help)
+	plain := flags.Bool("plain", false, "do not use colours nor clear the screen, also when NO_COLOR is set or stdout is not a terminal")
+	maxPoints := flags.Int("max-points", decide.DefaultMaxPoints, "the maximum number of points of an input")
+	evaluation := evaluationFlag(flags)
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitInput
+	}
+	input, err := getInput(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "unable to get the input file", err.Error())
+		return exitCode(err)
+	}
+	engine := &decide.Engine{MaxPoints: *maxPoints, Evaluation: *evaluation}
+	session, err := tui.NewSession(engine, path.Base(flags.Arg(0)), input)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitCode(err)
+	}
+	color := !*plain && os.Getenv("NO_COLOR") == ""
+	if fi, err := os.Stdout.Stat(); err != nil || fi.Mode() & os.ModeCharDevice == 0 {
+		color = false
+	}
+	if err := tui.Run(os.Stdin, os.Stdout, session, color); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitInternal
+	}
+	return exitOK
+}
@@ -0,0 +1,406 @@
+// Package tui explores a decision in a terminal: the PUV, LCM and
+// parameters of an input are edited with short commands, and the CMV,
+// PUM and FUV are evaluated again after each of them, with the cells that
+// changed highlighted.
+package tui
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/tdurieux/go-decide/decide"
+)
+
+// Help describes the commands of a session.
+const Help = `Commands:
+  puv <lic>              toggle PUV[lic]
+  lcm <i> <j> [and|or|notused]
+                         set LCM[i][j] and LCM[j][i], or cycle them through
+                         and, or and notused
+  set <PARAMETER> <value>
+                         set a parameter, e.g. set RADIUS1 2.5
+  undo                   undo the last change
+  help                   print this help
+  quit                   leave, also q or the end of the input`
+
+// ANSI escape sequences of the display.
+const (
+	clear     = "\x1b[H\x1b[2J"
+	reset     = "\x1b[0m"
+	green     = "\x1b[30;42m"
+	red       = "\x1b[30;41m"
+	grey      = "\x1b[90m"
+	highlight = "\x1b[1;30;43m"
+)
+
+// Session is an input being explored and its decision.
+type Session struct {
+	Name   string
+	engine *decide.Engine
+	input  decide.INPUT
+	// history holds the inputs before each change, for undo.
+	history  []decide.INPUT
+	decision decide.Decide
+	// before and previous are the input and the decision before the last
+	// change, to highlight the cells that changed.
+	before   decide.INPUT
+	previous decide.Decide
+	// err is the error of the evaluation of the current input, whose
+	// decision is then the last one that succeeded.
+	err     error
+	message string
+}
+
+// NewSession decides input with engine and returns a session that
+// explores it. The input is copied.
+func NewSession(engine *decide.Engine, name string, input decide.INPUT) (*Session, error) {
+	s := &Session{Name: name, engine: engine, input: clone(input)}
+	if err := s.evaluate(); err != nil {
+		return nil, err
+	}
+	s.before = s.input
+	s.previous = s.decision
+	return s, nil
+}
+
+func clone(input decide.INPUT) decide.INPUT {
+	lcm := make(map[string][decide.NB_LIC]decide.Command, len(input.LCM))
+	for k, row := range input.LCM {
+		lcm[k] = row
+	}
+	input.LCM = lcm
+	input.Points = append([][2]float64(nil), input.Points...)
+	return input
+}
+
+// Input returns the current input of the session.
+func (s *Session) Input() decide.INPUT {
+	return clone(s.input)
+}
+
+// Decision returns the decision of the current input, or of the last
+// input that could be decided when Err is not nil.
+func (s *Session) Decision() decide.Decide {
+	return s.decision
+}
+
+// Err returns the error of the evaluation of the current input.
+func (s *Session) Err() error {
+	return s.err
+}
+
+func (s *Session) evaluate() error {
+	d, err := s.engine.Decide(context.Background(), s.input)
+	s.err = err
+	if err == nil {
+		s.decision = d
+	}
+	return err
+}
+
+// change applies edit to a copy of the input and decides it again.
+func (s *Session) change(edit func(input *decide.INPUT) error) error {
+	input := clone(s.input)
+	if err := edit(&input); err != nil {
+		return err
+	}
+	s.history = append(s.history, s.input)
+	s.before = s.input
+	s.input = input
+	s.previous = s.decision
+	s.evaluate()
+	return nil
+}
+
+func lic(field string) (int, error) {
+	i, err := strconv.Atoi(field)
+	if err != nil || i < 0 || i >= decide.NB_LIC {
+		return 0, fmt.Errorf("%q is not a LIC", field)
+	}
+	return i, nil
+}
+
+// connectors maps the names of the commands to the LCM connectors, in
+// the order lcm cycles through them.
+var connectors = []struct {
+	name    string
+	command decide.Command
+}{
+	{"and", decide.ANDD},
+	{"or", decide.ORR},
+	{"notused", decide.NOTUSED},
+}
+
+// Execute runs a command and reports whether it ends the session. An
+// error is returned for an invalid command, which changes nothing; an
+// input that cannot be decided is reported by Err instead.
+func (s *Session) Execute(line string) (bool, error) {
+	fields := strings.Fields(line)
+	s.message = ""
+	if len(fields) == 0 {
+		return false, nil
+	}
+	switch fields[0] {
+	case "q", "quit", "exit":
+		return true, nil
+	case "help", "?":
+		s.message = Help
+		return false, nil
+	case "undo":
+		if len(s.history) == 0 {
+			return false, fmt.Errorf("nothing to undo")
+		}
+		s.before = s.input
+		s.input = s.history[len(s.history) - 1]
+		s.history = s.history[:len(s.history) - 1]
+		s.previous = s.decision
+		s.evaluate()
+		return false, nil
+	case "puv":
+		if len(fields) != 2 {
+			return false, fmt.Errorf("usage: puv <lic>")
+		}
+		i, err := lic(fields[1])
+		if err != nil {
+			return false, err
+		}
+		return false, s.change(func(input *decide.INPUT) error {
+			input.PUV[i] = !input.PUV[i]
+			return nil
+		})
+	case "lcm":
+		if len(fields) != 3 && len(fields) != 4 {
+			return false, fmt.Errorf("usage: lcm <i> <j> [and|or|notused]")
+		}
+		i, err := lic(fields[1])
+		if err != nil {
+			return false, err
+		}
+		j, err := lic(fields[2])
+		if err != nil {
+			return false, err
+		}
+		return false, s.change(func(input *decide.INPUT) error {
+			rowI, rowJ := input.LCM[strconv.Itoa(i)], input.LCM[strconv.Itoa(j)]
+			next := -1
+			if len(fields) == 4 {
+				for k, c := range connectors {
+					if c.name == strings.ToLower(fields[3]) {
+						next = k
+					}
+				}
+				if next < 0 {
+					return fmt.Errorf("%q is not a connector: and, or or notused", fields[3])
+				}
+			} else {
+				next = 0
+				for k, c := range connectors {
+					if c.command == rowI[j] {
+						next = (k + 1) % len(connectors)
+					}
+				}
+			}
+			rowI[j] = connectors[next].command
+			input.LCM[strconv.Itoa(i)] = rowI
+			if i != j {
+				rowJ[i] = connectors[next].command
+				input.LCM[strconv.Itoa(j)] = rowJ
+			}
+			return nil
+		})
+	case "set":
+		if len(fields) != 3 {
+			return false, fmt.Errorf("usage: set <PARAMETER> <value>")
+		}
+		return false, s.change(func(input *decide.INPUT) error {
+			return setParameter(&input.Parameters, strings.ToUpper(fields[1]), fields[2])
+		})
+	}
+	return false, fmt.Errorf("unknown command %q, type help for the commands", fields[0])
+}
+
+// setParameter sets the field name of p to value.
+func setParameter(p *decide.Parameters, name string, value string) error {
+	field := reflect.ValueOf(p).Elem().FieldByName(name)
+	if !field.IsValid() {
+		return fmt.Errorf("unknown parameter %q", name)
+	}
+	switch field.Kind() {
+	case reflect.Int:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s must be an integer", name)
+		}
+		field.SetInt(int64(i))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s must be a number", name)
+		}
+		field.SetFloat(f)
+	}
+	return nil
+}
+
+// screen writes the display of a session.
+type screen struct {
+	w     *bufio.Writer
+	color bool
+}
+
+// cell writes a cell of a grid: text on a green or red background for a
+// true or false value, highlighted when it changed.
+func (sc screen) cell(text string, value bool, changed bool, dim bool) {
+	switch {
+	case !sc.color:
+		mark := " "
+		if changed {
+			mark = "*"
+		}
+		fmt.Fprintf(sc.w, " %s%s", text, mark)
+	case changed:
+		fmt.Fprintf(sc.w, " %s%s %s", highlight, text, reset)
+	case dim:
+		fmt.Fprintf(sc.w, " %s%s %s", grey, text, reset)
+	case value:
+		fmt.Fprintf(sc.w, " %s%s %s", green, text, reset)
+	default:
+		fmt.Fprintf(sc.w, " %s%s %s", red, text, reset)
+	}
+}
+
+func mark(v bool) string {
+	if v {
+		return "V"
+	}
+	return "X"
+}
+
+func (sc screen) header(title string) {
+	fmt.Fprintf(sc.w, "%-6s", title)
+	for i := 0; i < decide.NB_LIC; i++ {
+		fmt.Fprintf(sc.w, " %-2d", i)
+	}
+	fmt.Fprintln(sc.w)
+}
+
+func (sc screen) row(title string, values [decide.NB_LIC]bool, previous [decide.NB_LIC]bool, dim [decide.NB_LIC]bool) {
+	fmt.Fprintf(sc.w, "%-6s", title)
+	for i, v := range values {
+		sc.cell(mark(v), v, v != previous[i], dim[i])
+	}
+	fmt.Fprintln(sc.w)
+}
+
+// Render writes the display of the session to w: the LAUNCH decision,
+// the parameters, the CMV, PUV and FUV rows, and the PUM and LCM grids.
+// With color, the cells are coloured and the screen is cleared first;
+// otherwise the cells that changed are marked with a *.
+func (s *Session) Render(w io.Writer, color bool) error {
+	sc := screen{bufio.NewWriter(w), color}
+	d, p := s.decision, s.previous
+	if color {
+		sc.w.WriteString(clear)
+	}
+	launch := "LAUNCH " + d.Launch
+	if d.Launch != p.Launch {
+		launch += " (was " + p.Launch + ")"
+	}
+	fmt.Fprintf(sc.w, "%s  NUMPOINTS %d  %s\n", s.Name, s.input.NumPoints, launch)
+	v := reflect.ValueOf(s.input.Parameters)
+	for i := 0; i < v.NumField(); i++ {
+		fmt.Fprintf(sc.w, "%s=%v", v.Type().Field(i).Name, v.Field(i).Interface())
+		if i == v.NumField() - 1 || i % 8 == 7 {
+			fmt.Fprintln(sc.w)
+		} else {
+			sc.w.WriteString("  ")
+		}
+	}
+	fmt.Fprintln(sc.w)
+
+	var none, skipped [decide.NB_LIC]bool
+	for _, lic := range d.NotEvaluated {
+		skipped[lic] = true
+	}
+	sc.header("")
+	sc.row("CMV", d.CMV, p.CMV, skipped)
+	sc.row("PUV", s.input.PUV, s.before.PUV, none)
+	sc.row("FUV", d.FUV, p.FUV, none)
+	fmt.Fprintln(sc.w)
+
+	sc.header("PUM")
+	for i, row := range d.PUM {
+		var diagonal [decide.NB_LIC]bool
+		diagonal[i] = true
+		fmt.Fprintf(sc.w, "%-6d", i)
+		blocking := 0
+		for j, v := range row {
+			sc.cell(mark(v), v, v != p.PUM[i][j], diagonal[j])
+			if !v && i != j {
+				blocking++
+			}
+		}
+		fmt.Fprintf(sc.w, "  %d false\n", blocking)
+	}
+	fmt.Fprintln(sc.w)
+
+	sc.header("LCM")
+	for i := 0; i < decide.NB_LIC; i++ {
+		row := s.input.LCM[strconv.Itoa(i)]
+		fmt.Fprintf(sc.w, "%-6d", i)
+		for j, c := range row {
+			text, value := "?", false
+			switch c {
+			case decide.ANDD:
+				text, value = "&", true
+			case decide.ORR:
+				text, value = "|", true
+			case decide.NOTUSED:
+				text = "-"
+			}
+			changed := s.before.LCM[strconv.Itoa(i)][j] != c
+			sc.cell(text, value, changed, c == decide.NOTUSED && !changed)
+		}
+		fmt.Fprintln(sc.w)
+	}
+	fmt.Fprintln(sc.w, "LCM: & and, | or, - not used")
+
+	if s.err != nil {
+		fmt.Fprintf(sc.w, "\nThe input cannot be decided, the last decision is shown: %v\n", s.err)
+	}
+	if s.message != "" {
+		fmt.Fprintf(sc.w, "\n%s\n", s.message)
+	}
+	return sc.w.Flush()
+}
+
+// Run reads commands from r, one per line, and writes the display of
+// the session to w after each of them, until a quit command or the end
+// of r.
+func Run(r io.Reader, w io.Writer, s *Session, color bool) error {
+	scanner := bufio.NewScanner(r)
+	for {
+		if err := s.Render(w, color); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "> "); err != nil {
+			return err
+		}
+		if !scanner.Scan() {
+			io.WriteString(w, "\n")
+			return scanner.Err()
+		}
+		quit, err := s.Execute(scanner.Text())
+		if quit {
+			return nil
+		}
+		if err != nil {
+			s.message = err.Error()
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tdurieux/go-decide/decide"
+)
+
+func newSession(t *testing.T) *Session {
+	s, err := NewSession(&decide.Engine{}, "generated", decide.Generate(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestPUV(t *testing.T) {
+	s := newSession(t)
+	d := s.Decision()
+	lic := -1
+	for i, v := range d.FUV {
+		if !v {
+			lic = i
+			break
+		}
+	}
+	if lic < 0 {
+		t.Fatal("Expected a false FUV entry")
+	}
+	if _, err := s.Execute("puv " + strconv.Itoa(lic)); err != nil {
+		t.Error(err)
+		return
+	}
+	if s.Input().PUV[lic] || !s.Decision().FUV[lic] {
+		t.Error("Expected FUV", lic, "to be true once its PUV entry is unset")
+		return
+	}
+	var b strings.Builder
+	if err := s.Render(&b, false); err != nil {
+		t.Error(err)
+		return
+	}
+	if !strings.Contains(b.String(), "PUV") || strings.Count(b.String(), "*") != 2 {
+		t.Error("Expected the changed PUV and FUV cells to be marked, got", b.String())
+		return
+	}
+
+	if _, err := s.Execute("undo"); err != nil {
+		t.Error(err)
+		return
+	}
+	if s.Decision().FUV != d.FUV || s.Input().PUV != decide.Generate(1).PUV {
+		t.Error("Expected undo to restore the input and its decision")
+		return
+	}
+	if _, err := s.Execute("undo"); err == nil {
+		t.Error("Expected nothing to undo")
+		return
+	}
+}
+
+func TestLCM(t *testing.T) {
+	s := newSession(t)
+	if _, err := s.Execute("lcm 2 5 and"); err != nil {
+		t.Error(err)
+		return
+	}
+	input := s.Input()
+	if input.LCM["2"][5] != decide.ANDD || input.LCM["5"][2] != decide.ANDD {
+		t.Error("Expected LCM[2][5] and LCM[5][2] to be set")
+		return
+	}
+	d := s.Decision()
+	if d.PUM[2][5] != (d.CMV[2] && d.CMV[5]) {
+		t.Error("Expected the PUM to be evaluated again")
+		return
+	}
+	for _, expected := range []decide.Command{decide.ORR, decide.NOTUSED, decide.ANDD} {
+		if _, err := s.Execute("lcm 2 5"); err != nil {
+			t.Error(err)
+			return
+		}
+		if s.Input().LCM["5"][2] != expected {
+			t.Error("Expected lcm to cycle to", expected, "got", s.Input().LCM["5"][2])
+			return
+		}
+	}
+	for _, command := range []string{"lcm 2 15 and", "lcm 2 5 xor", "lcm 2"} {
+		if _, err := s.Execute(command); err == nil {
+			t.Error("Expected", command, "to be rejected")
+			return
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	s := newSession(t)
+	if _, err := s.Execute("set radius1 0"); err != nil {
+		t.Error(err)
+		return
+	}
+	if s.Input().Parameters.RADIUS1 != 0 || s.Err() != nil {
+		t.Error("Expected RADIUS1 to be set and the input decided")
+		return
+	}
+	d := s.Decision()
+	if _, err := s.Execute("set EPSILON 4"); err != nil {
+		t.Error(err)
+		return
+	}
+	if s.Err() == nil || s.Decision().CMV != d.CMV {
+		t.Error("Expected an invalid EPSILON to be reported and the last decision kept")
+		return
+	}
+	var b strings.Builder
+	if err := s.Render(&b, true); err != nil {
+		t.Error(err)
+		return
+	}
+	if !strings.Contains(b.String(), "cannot be decided") || !strings.HasPrefix(b.String(), clear) {
+		t.Error("Expected the error to be displayed, got", b.String())
+		return
+	}
+	for _, command := range []string{"set RADIUS3 1", "set Q_PTS 2.5", "set LENGTH1 far", "jump"} {
+		if _, err := s.Execute(command); err == nil {
+			t.Error("Expected", command, "to be rejected")
+			return
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	s := newSession(t)
+	var b strings.Builder
+	if err := Run(strings.NewReader("help\npuv 0\nbogus\nq\nset RADIUS1 1\n"), &b, s, false); err != nil {
+		t.Error(err)
+		return
+	}
+	out := b.String()
+	if strings.Count(out, "LAUNCH") != 4 || !strings.Contains(out, "undo the last change") || !strings.Contains(out, `unknown command "bogus"`) {
+		t.Error("Expected a display after each command until quit, got", out)
+		return
+	}
+	if s.Input().Parameters.RADIUS1 == 1 && decide.Generate(1).Parameters.RADIUS1 != 1 {
+		t.Error("Expected the commands after quit to be ignored")
+		return
+	}
+}
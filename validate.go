@@ -0,0 +1,46 @@
+package main
+
+import (
+	"github.com/tdurieux/go-decide/decide"
+	"fmt"
+	"os"
+)
+
+func validateCmd(args []string) int {
+	flags := newFlagSet("validate", "input...",
+		"Checks the structure of the inputs and the constraints of their\n" +
+		"parameters without evaluating any LIC.")
+	maxPoints := flags.Int("max-points", decide.DefaultMaxPoints, "the maximum NUMPOINTS of an input")
+	bounds := boundsFlag(flags)
+	if code := parseFlags(flags, args); code >= 0 {
+		return code
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitInput
+	}
+
+	engine := &decide.Engine{MaxPoints: *maxPoints, Bounds: *bounds}
+	code := exitOK
+	for _, arg := range flags.Args() {
+		files, err := inputFiles(arg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = max(code, exitCode(err))
+			continue
+		}
+		for _, file := range files {
+			input, err := getInput(file)
+			if err == nil {
+				err = engine.Validate(input)
+			}
+			if err != nil {
+				fmt.Printf("%s: %s\n", file, err)
+				code = max(code, exitCode(err))
+				continue
+			}
+			fmt.Printf("%s: OK\n", file)
+		}
+	}
+	return code
+}